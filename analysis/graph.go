@@ -0,0 +1,300 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+var (
+	// ErrDependencyCycle is returned by `Graph.Validate()` for each dependency cycle.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrUndeclaredReference is returned by `Graph.Validate()` for each reference to an undeclared object.
+	ErrUndeclaredReference = errors.New("reference to undeclared object")
+)
+
+// Graph is a dependency graph between blocks
+//
+// Nodes are addressed like references: `var.x` for `variable` blocks, `local.y` for each attribute of `locals` blocks,
+// `module.m`, `aws_x.y` for `resource` blocks, `data.a.b`, `output.o` and `provider.p` (or `provider.p.alias`).
+// Other blocks (e.g. `terraform`, `import` or `moved`) are ignored.
+type Graph struct {
+	nodes      []string
+	references map[string][]Reference
+}
+
+// NewGraph builds the dependency graph of the provided blocks, in the provided order.
+func NewGraph(blocks ...*tfsig.BlockSignature) *Graph {
+	graph := &Graph{nodes: []string{}, references: map[string][]Reference{}}
+
+	for _, sig := range blocks {
+		if sig != nil {
+			graph.addBlock(sig)
+		}
+	}
+
+	return graph
+}
+
+// NewFileGraph builds the dependency graph of blocks of the provided file.
+func NewFileGraph(file *tfsig.FileSignature) *Graph {
+	return NewGraph(file.GetBlocks()...)
+}
+
+// NewProjectGraph builds the dependency graph of blocks of all files of the provided project
+// (files are walked in creation order).
+func NewProjectGraph(project *tfsig.ProjectSignature) *Graph {
+	blocks := []*tfsig.BlockSignature{}
+	for _, filename := range project.GetFilenames() {
+		blocks = append(blocks, project.GetFile(filename).GetBlocks()...)
+	}
+
+	return NewGraph(blocks...)
+}
+
+// GetNodes returns addresses of declared nodes, in declaration order.
+func (g *Graph) GetNodes() []string {
+	return append([]string{}, g.nodes...)
+}
+
+// GetReferences returns references of the provided node, in order of appearance.
+func (g *Graph) GetReferences(node string) []Reference {
+	return append([]Reference{}, g.references[node]...)
+}
+
+// GetDependencies returns sorted addresses of declared nodes the provided node depends on.
+func (g *Graph) GetDependencies(node string) []string {
+	deps := []string{}
+
+	for _, ref := range g.references[node] {
+		if _, declared := g.references[ref.Address]; declared && !slices.Contains(deps, ref.Address) {
+			deps = append(deps, ref.Address)
+		}
+	}
+
+	slices.Sort(deps)
+
+	return deps
+}
+
+// GetUndeclaredReferences returns references to objects which are not declared in the graph, in declaration order.
+func (g *Graph) GetUndeclaredReferences() []Reference {
+	refs := []Reference{}
+
+	for _, node := range g.nodes {
+		for _, ref := range g.references[node] {
+			if _, declared := g.references[ref.Address]; !declared {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	return refs
+}
+
+// GetCycles returns dependency cycles, each cycle being a list of nodes in declaration order
+// (a node referencing itself is a cycle).
+func (g *Graph) GetCycles() [][]string {
+	finder := &cycleFinder{
+		graph:   g,
+		counter: 0,
+		index:   map[string]int{},
+		lowLink: map[string]int{},
+		onStack: map[string]bool{},
+		stack:   []string{},
+		cycles:  [][]string{},
+	}
+	for _, node := range g.nodes {
+		if _, visited := finder.index[node]; !visited {
+			finder.visit(node)
+		}
+	}
+
+	positions := map[string]int{}
+	for idx, node := range g.nodes {
+		positions[node] = idx
+	}
+
+	for _, cycle := range finder.cycles {
+		sort.Slice(cycle, func(i, j int) bool { return positions[cycle[i]] < positions[cycle[j]] })
+	}
+
+	sort.Slice(finder.cycles, func(i, j int) bool {
+		return positions[finder.cycles[i][0]] < positions[finder.cycles[j][0]]
+	})
+
+	return finder.cycles
+}
+
+// Validate returns an error for each dependency cycle (see `ErrDependencyCycle`) and for each reference to an
+// undeclared object (see `ErrUndeclaredReference`).
+func (g *Graph) Validate() error {
+	errs := []error{}
+
+	for _, cycle := range g.GetCycles() {
+		errs = append(errs, fmt.Errorf("%w between %s", ErrDependencyCycle, strings.Join(cycle, ", ")))
+	}
+
+	for _, ref := range g.GetUndeclaredReferences() {
+		errs = append(errs, fmt.Errorf("%s: %w %s", ref.Path, ErrUndeclaredReference, ref.Address))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DOT returns the graph in DOT format, an edge goes from a node to each of its dependencies.
+func (g *Graph) DOT() string {
+	builder := &strings.Builder{}
+	builder.WriteString("digraph {\n")
+
+	for _, node := range g.nodes {
+		builder.WriteString("  " + strconv.Quote(node) + ";\n")
+	}
+
+	for _, node := range g.nodes {
+		for _, dep := range g.GetDependencies(node) {
+			builder.WriteString("  " + strconv.Quote(node) + " -> " + strconv.Quote(dep) + ";\n")
+		}
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+/** Private **/
+
+func (g *Graph) addBlock(sig *tfsig.BlockSignature) {
+	if sig.GetType() == "locals" {
+		for _, elem := range sig.GetElements() {
+			if elem.IsBodyAttribute() {
+				path := sig.GetHeader() + pathSeparator + elem.GetName()
+				g.addNode("local."+elem.GetName(), extractReferences(*elem.GetBodyAttribute(), path, nil))
+			}
+		}
+
+		return
+	}
+
+	if address, ok := blockAddress(sig); ok {
+		g.addNode(address, BlockReferences(sig))
+	}
+}
+
+func (g *Graph) addNode(address string, refs []Reference) {
+	if _, exists := g.references[address]; !exists {
+		g.nodes = append(g.nodes, address)
+	}
+
+	g.references[address] = append(g.references[address], refs...)
+}
+
+// blockAddress returns the address of the node related to the block and whether the block is a node.
+func blockAddress(sig *tfsig.BlockSignature) (string, bool) {
+	labels := sig.GetLabels()
+
+	switch {
+	case sig.GetType() == "resource" && len(labels) == 2:
+		return labels[0] + addressSeparator + labels[1], true
+	case sig.GetType() == "data" && len(labels) == 2:
+		return "data" + addressSeparator + labels[0] + addressSeparator + labels[1], true
+	case len(labels) == 1:
+		return singleLabelBlockAddress(sig)
+	}
+
+	return "", false
+}
+
+// singleLabelBlockAddress returns the address of the node related to a block having a single label and whether
+// the block is a node.
+func singleLabelBlockAddress(sig *tfsig.BlockSignature) (string, bool) {
+	switch sig.GetType() {
+	case "variable":
+		return "var" + addressSeparator + sig.GetLabels()[0], true
+	case "module", "output":
+		return sig.GetType() + addressSeparator + sig.GetLabels()[0], true
+	case "provider":
+		return providerAddress(sig), true
+	}
+
+	return "", false
+}
+
+func providerAddress(sig *tfsig.BlockSignature) string {
+	address := "provider" + addressSeparator + sig.GetLabels()[0]
+
+	for _, elem := range sig.GetElements() {
+		if !elem.IsBodyAttribute() || elem.GetName() != "alias" {
+			continue
+		}
+
+		if alias := *elem.GetBodyAttribute(); !alias.IsNull() && alias.Type() == cty.String {
+			return address + addressSeparator + alias.AsString()
+		}
+	}
+
+	return address
+}
+
+// cycleFinder implements Tarjan's strongly connected components algorithm.
+type cycleFinder struct {
+	graph   *Graph
+	counter int
+	index   map[string]int
+	lowLink map[string]int
+	onStack map[string]bool
+	stack   []string
+	cycles  [][]string
+}
+
+func (f *cycleFinder) visit(node string) {
+	f.index[node] = f.counter
+	f.lowLink[node] = f.counter
+	f.counter++
+	f.stack = append(f.stack, node)
+	f.onStack[node] = true
+
+	selfLoop := false
+
+	for _, dep := range f.graph.GetDependencies(node) {
+		if dep == node {
+			selfLoop = true
+		}
+
+		if _, visited := f.index[dep]; !visited {
+			f.visit(dep)
+			f.lowLink[node] = min(f.lowLink[node], f.lowLink[dep])
+		} else if f.onStack[dep] {
+			f.lowLink[node] = min(f.lowLink[node], f.index[dep])
+		}
+	}
+
+	if f.lowLink[node] != f.index[node] {
+		return
+	}
+
+	component := []string{}
+
+	for {
+		last := f.stack[len(f.stack)-1]
+		f.stack = f.stack[:len(f.stack)-1]
+		f.onStack[last] = false
+
+		component = append(component, last)
+
+		if last == node {
+			break
+		}
+	}
+
+	if len(component) > 1 || selfLoop {
+		f.cycles = append(f.cycles, component)
+	}
+}
@@ -0,0 +1,42 @@
+package analysis_test
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/analysis"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func ExampleGraph_DOT() {
+	variable := tfsig.NewSignature("variable", "bucket_name")
+
+	bucket := tfsig.NewResource("aws_s3_bucket", "logs")
+	bucket.AppendAttribute("bucket", *tokens.NewIdentValue("var.bucket_name"))
+
+	policy := tfsig.NewResource("aws_s3_bucket_policy", "logs")
+	policy.AppendAttribute("bucket", *tokens.NewIdentValue("aws_s3_bucket.logs.id"))
+	policy.AppendAttribute("policy", *tokens.NewIdentValue("data.aws_iam_policy_document.logs.json"))
+
+	output := tfsig.NewSignature("output", "arn")
+	output.AppendAttribute("value", *tokens.NewIdentValue("aws_s3_bucket.logs.arn"))
+	output.AppendAttribute("sensitive", cty.False)
+
+	graph := analysis.NewFileGraph(tfsig.NewFileSignature(variable, bucket, policy, output))
+
+	fmt.Print(graph.DOT())
+	fmt.Println(graph.Validate())
+	// Output:
+	// digraph {
+	//   "var.bucket_name";
+	//   "aws_s3_bucket.logs";
+	//   "aws_s3_bucket_policy.logs";
+	//   "output.arn";
+	//   "aws_s3_bucket.logs" -> "var.bucket_name";
+	//   "aws_s3_bucket_policy.logs" -> "aws_s3_bucket.logs";
+	//   "output.arn" -> "aws_s3_bucket.logs";
+	// }
+	// resource "aws_s3_bucket_policy" "logs" > policy: reference to undeclared object data.aws_iam_policy_document.logs
+}
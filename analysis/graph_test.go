@@ -0,0 +1,158 @@
+package analysis_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/analysis"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func newBlock(blockType string, labels []string, attrs map[string]string) *tfsig.BlockSignature {
+	sig := tfsig.NewSignature(blockType, labels...)
+
+	for _, name := range []string{"a", "b", "c"} {
+		if expr, exists := attrs[name]; exists {
+			sig.AppendAttribute(name, *tokens.NewExpressionValue(expr))
+		}
+	}
+
+	return sig
+}
+
+func TestNewGraph(t *testing.T) {
+	t.Parallel()
+
+	provider := tfsig.NewSignature("provider", "aws")
+	provider.AppendAttribute("alias", cty.StringVal("eu"))
+	provider.AppendAttribute("region", *tokens.NewIdentValue("var.region"))
+
+	instance := newBlock("resource", []string{"aws_instance", "web"}, map[string]string{"a": "data.aws_ami.ubuntu.id"})
+	instance.AppendAttribute("provider", *tokens.NewIdentValue("aws.eu"))
+
+	module := newBlock("module", []string{"network"}, map[string]string{"a": "aws_instance.web.id"})
+	module.AppendAttribute("providers", *tokens.NewExpressionValue("{ aws = aws.eu }"))
+
+	graph := analysis.NewGraph(
+		tfsig.NewSignature("terraform"),
+		newBlock("variable", []string{"region"}, nil),
+		provider,
+		newBlock("locals", nil, map[string]string{"a": "var.region", "b": "local.a"}),
+		newBlock("data", []string{"aws_ami", "ubuntu"}, map[string]string{"a": "local.b"}),
+		instance,
+		module,
+		newBlock("output", []string{"ip"}, map[string]string{"a": "module.network.ip", "b": "var.region"}),
+		nil,
+	)
+
+	expectedNodes := []string{
+		"var.region", "provider.aws.eu", "local.a", "local.b", "data.aws_ami.ubuntu", "aws_instance.web",
+		"module.network", "output.ip",
+	}
+	if actual := graph.GetNodes(); !reflect.DeepEqual(actual, expectedNodes) {
+		t.Errorf("expected %v, got %v", expectedNodes, actual)
+	}
+
+	expectedDeps := map[string][]string{
+		"var.region":       {},
+		"provider.aws.eu":  {"var.region"},
+		"local.b":          {"local.a"},
+		"aws_instance.web": {"data.aws_ami.ubuntu", "provider.aws.eu"},
+		"module.network":   {"aws_instance.web", "provider.aws.eu"},
+		"output.ip":        {"module.network", "var.region"},
+	}
+	for node, expected := range expectedDeps {
+		if actual := graph.GetDependencies(node); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Case \"%s\": expected %v, got %v", node, expected, actual)
+		}
+	}
+
+	if err := graph.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestGraph_GetCycles(t *testing.T) {
+	t.Parallel()
+
+	graph := analysis.NewGraph(
+		newBlock("locals", nil, map[string]string{"a": "local.b", "b": "local.c", "c": "local.a"}),
+		newBlock("resource", []string{"x", "self"}, map[string]string{"a": "x.self.id"}),
+		newBlock("resource", []string{"x", "y"}, map[string]string{"a": "x.z.id"}),
+		newBlock("resource", []string{"x", "z"}, map[string]string{"a": "x.y.id"}),
+		newBlock("resource", []string{"x", "ok"}, map[string]string{"a": "x.y.id"}),
+	)
+
+	expected := [][]string{{"local.a", "local.b", "local.c"}, {"x.self"}, {"x.y", "x.z"}}
+	if actual := graph.GetCycles(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	err := graph.Validate()
+	if !errors.Is(err, analysis.ErrDependencyCycle) {
+		t.Fatalf("expected %v, got %v", analysis.ErrDependencyCycle, err)
+	}
+
+	expectedMessage := `dependency cycle between local.a, local.b, local.c
+dependency cycle between x.self
+dependency cycle between x.y, x.z`
+	if err.Error() != expectedMessage {
+		t.Errorf("expected\n%s\ngot\n%s", expectedMessage, err)
+	}
+}
+
+func TestGraph_GetUndeclaredReferences(t *testing.T) {
+	t.Parallel()
+
+	graph := analysis.NewFileGraph(tfsig.NewFileSignature(
+		newBlock("variable", []string{"declared"}, nil),
+		newBlock("resource", []string{"x", "y"}, map[string]string{
+			"a": "var.declared",
+			"b": "var.undeclared",
+			"c": "data.a.b.id",
+		}),
+		newBlock("locals", nil, map[string]string{"a": "local.missing"}),
+	))
+
+	expected := []analysis.Reference{
+		{Address: "var.undeclared", Expression: "var.undeclared", Path: `resource "x" "y" > b`},
+		{Address: "data.a.b", Expression: "data.a.b.id", Path: `resource "x" "y" > c`},
+		{Address: "local.missing", Expression: "local.missing", Path: "locals > a"},
+	}
+	if actual := graph.GetUndeclaredReferences(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+
+	err := graph.Validate()
+	if !errors.Is(err, analysis.ErrUndeclaredReference) {
+		t.Fatalf("expected %v, got %v", analysis.ErrUndeclaredReference, err)
+	}
+
+	expectedMessage := `resource "x" "y" > b: reference to undeclared object var.undeclared
+resource "x" "y" > c: reference to undeclared object data.a.b
+locals > a: reference to undeclared object local.missing`
+	if err.Error() != expectedMessage {
+		t.Errorf("expected\n%s\ngot\n%s", expectedMessage, err)
+	}
+}
+
+func TestNewProjectGraph(t *testing.T) {
+	t.Parallel()
+
+	project := tfsig.NewProjectSignature(tfsig.NewDefaultFileRouter())
+	project.AppendBlock(newBlock("resource", []string{"x", "y"}, map[string]string{"a": "var.name"}))
+	project.AppendBlock(newBlock("variable", []string{"name"}, nil))
+
+	graph := analysis.NewProjectGraph(project)
+	if err := graph.Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if actual := graph.GetDependencies("x.y"); !reflect.DeepEqual(actual, []string{"var.name"}) {
+		t.Errorf("expected [var.name], got %v", actual)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Package analysis provides a way to extract references from signatures and to build a dependency graph between blocks,
+in order to detect cycles and references to undeclared objects
+
+References are addresses of objects used in expressions, like `var.x` or `aws_s3_bucket.b` for `aws_s3_bucket.b.arn`.
+*/
+package analysis
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+const (
+	dynamicBlockType     = "dynamic"
+	dynamicIteratorAttr  = "iterator"
+	dynamicContentBlock  = "content"
+	pathSeparator        = " > "
+	addressSeparator     = "."
+	minResourceAttrCount = 1
+	minDataAttrCount     = 2
+	maxProviderAttrCount = 1
+)
+
+//nolint:gochecknoglobals // Better to keep it as **internal** global var than define it each time
+var providerMetaArguments = map[string]string{"resource": "provider", "data": "provider", "module": "providers"}
+
+//nolint:gochecknoglobals // Better to keep it as **internal** global var than define it each time
+var ignoredRootNames = map[string]bool{"count": true, "each": true, "self": true, "path": true, "terraform": true}
+
+// Reference is a reference to an object found in an attribute value.
+type Reference struct {
+	// Address of the referenced object (e.g. `var.x`, `local.y`, `module.m`, `aws_x.y` or `data.a.b`)
+	Address string
+	// Expression is the full reference as written (e.g. `module.m.out` or `aws_x.y.attr`)
+	Expression string
+	// Path is the location of the attribute holding the reference (e.g. `resource "aws_x" "y" > child > attr`)
+	Path string
+}
+
+// ExtractReferences returns references found in the provided value, in order of appearance
+//
+// Only expressions (see `tokens.NewIdentValue()` and `tokens.NewExpressionValue()`) can hold references,
+// literal values (including strings containing `${...}`) never do. Unknown values are ignored.
+func ExtractReferences(value cty.Value) []Reference {
+	return extractReferences(value, "", nil)
+}
+
+// BlockReferences returns references found in attributes of the provided block and of its children
+//
+// Iterators of `dynamic` blocks (e.g. `setting.value`) are not considered as references.
+// Provider configurations used by `provider` and `providers` meta-arguments (e.g. `aws.eu`) are addressed as
+// `provider.aws.eu`.
+func BlockReferences(sig *tfsig.BlockSignature) []Reference {
+	return blockReferences([]string{sig.GetHeader()}, sig, nil)
+}
+
+/** Private **/
+
+func blockReferences(path []string, sig *tfsig.BlockSignature, scope map[string]bool) []Reference {
+	refs := []Reference{}
+	contentScope := scope
+
+	if sig.GetType() == dynamicBlockType && len(sig.GetLabels()) == 1 {
+		contentScope = withScope(scope, dynamicIterator(sig))
+	}
+
+	for _, elem := range sig.GetElements() {
+		switch {
+		case elem.IsBodyAttribute():
+			attrPath := strings.Join(append(append([]string{}, path...), elem.GetName()), pathSeparator)
+
+			if len(path) == 1 && providerMetaArguments[sig.GetType()] == elem.GetName() {
+				refs = append(refs, providerReferences(*elem.GetBodyAttribute(), attrPath)...)
+			} else {
+				refs = append(refs, extractReferences(*elem.GetBodyAttribute(), attrPath, scope)...)
+			}
+		case elem.IsBodyBlock():
+			child := elem.GetBodyBlock()
+			childScope := scope
+
+			if sig.GetType() == dynamicBlockType && child.GetType() == dynamicContentBlock {
+				childScope = contentScope
+			}
+
+			childPath := append(append([]string{}, path...), child.GetHeader())
+			refs = append(refs, blockReferences(childPath, child, childScope)...)
+		}
+	}
+
+	return refs
+}
+
+func extractReferences(value cty.Value, path string, scope map[string]bool) []Reference {
+	expr, src, ok := parseValue(value)
+	if !ok {
+		return nil
+	}
+
+	refs := []Reference{}
+
+	for _, traversal := range expr.Variables() {
+		if scope[traversal.RootName()] {
+			continue
+		}
+
+		address, isObject := traversalAddress(traversal)
+		if !isObject {
+			continue
+		}
+
+		srcRange := traversal.SourceRange()
+		refs = append(refs, Reference{
+			Address:    address,
+			Expression: string(src[srcRange.Start.Byte:srcRange.End.Byte]),
+			Path:       path,
+		})
+	}
+
+	return refs
+}
+
+// providerReferences returns references to provider configurations found in the value of a `provider` meta-argument
+// (e.g. `aws.eu`), or in values of a `providers` meta-argument (e.g. `{ aws = aws.eu }`).
+func providerReferences(value cty.Value, path string) []Reference {
+	expr, src, ok := parseValue(value)
+	if !ok {
+		return nil
+	}
+
+	traversals := expr.Variables()
+
+	if object, isObject := expr.(*hclsyntax.ObjectConsExpr); isObject {
+		// Keys are provider names inside the module, not references
+		traversals = []hcl.Traversal{}
+		for _, item := range object.Items {
+			traversals = append(traversals, item.ValueExpr.Variables()...)
+		}
+	}
+
+	refs := []Reference{}
+
+	for _, traversal := range traversals {
+		parts := []string{"provider", traversal.RootName()}
+
+		for _, step := range traversal[1:] {
+			attr, isAttr := step.(hcl.TraverseAttr)
+			if !isAttr || len(parts) > maxProviderAttrCount+1 {
+				break
+			}
+
+			parts = append(parts, attr.Name)
+		}
+
+		srcRange := traversal.SourceRange()
+		refs = append(refs, Reference{
+			Address:    strings.Join(parts, addressSeparator),
+			Expression: string(src[srcRange.Start.Byte:srcRange.End.Byte]),
+			Path:       path,
+		})
+	}
+
+	return refs
+}
+
+// parseValue parses the provided value as an HCL expression and returns it alongside its source, values without any
+// expression are ignored.
+//
+//nolint:ireturn // Expression type depends on the value
+func parseValue(value cty.Value) (hclsyntax.Expression, []byte, bool) {
+	if value.IsNull() || tokens.ValidateValue(value) != nil || !tokens.ContainsCapsule(&value) {
+		return nil, nil, false
+	}
+
+	src := hclwrite.Format(tokens.Generate(&value).Bytes())
+
+	expr, diags := hclsyntax.ParseExpression(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, false
+	}
+
+	return expr, src, true
+}
+
+// traversalAddress returns the address of the object referenced by the traversal and whether it is a reference to an
+// object which can be declared.
+func traversalAddress(traversal hcl.Traversal) (string, bool) {
+	root := traversal.RootName()
+	if ignoredRootNames[root] {
+		return "", false
+	}
+
+	attrCount := minResourceAttrCount
+	if root == "data" {
+		attrCount = minDataAttrCount
+	}
+
+	parts := []string{root}
+
+	for _, step := range traversal[1:] {
+		if len(parts) > attrCount {
+			break
+		}
+
+		attr, isAttr := step.(hcl.TraverseAttr)
+		if !isAttr {
+			return "", false
+		}
+
+		parts = append(parts, attr.Name)
+	}
+
+	if len(parts) <= attrCount {
+		return "", false
+	}
+
+	return strings.Join(parts, addressSeparator), true
+}
+
+// dynamicIterator returns the iterator name of a `dynamic` block (`iterator` attribute if defined, else the label).
+func dynamicIterator(sig *tfsig.BlockSignature) string {
+	for _, elem := range sig.GetElements() {
+		if !elem.IsBodyAttribute() || elem.GetName() != dynamicIteratorAttr {
+			continue
+		}
+
+		value := *elem.GetBodyAttribute()
+		if tokens.IsCapsuleType(value.Type()) {
+			return strings.TrimSpace(string(tokens.FromValue(value).Bytes()))
+		}
+	}
+
+	return sig.GetLabels()[0]
+}
+
+func withScope(scope map[string]bool, name string) map[string]bool {
+	newScope := map[string]bool{name: true}
+	for key := range scope {
+		newScope[key] = true
+	}
+
+	return newScope
+}
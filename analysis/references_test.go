@@ -0,0 +1,138 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/analysis"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestExtractReferences(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    cty.Value
+		expected []analysis.Reference
+	}{
+		"Literal value": {
+			value:    cty.StringVal("${var.x}"),
+			expected: []analysis.Reference{},
+		},
+		"Null value": {
+			value:    cty.NullVal(cty.String),
+			expected: []analysis.Reference{},
+		},
+		"Unknown value": {
+			value:    cty.UnknownVal(cty.String),
+			expected: []analysis.Reference{},
+		},
+		"Variable": {
+			value:    *tokens.NewIdentValue("var.x"),
+			expected: []analysis.Reference{{Address: "var.x", Expression: "var.x", Path: ""}},
+		},
+		"All kinds": {
+			value: *tokens.NewExpressionValue(
+				`"${local.y}-${module.m.out}" == aws_x.y.attr ? data.a.b.c[0] : count.index + each.key`,
+			),
+			expected: []analysis.Reference{
+				{Address: "local.y", Expression: "local.y", Path: ""},
+				{Address: "module.m", Expression: "module.m.out", Path: ""},
+				{Address: "aws_x.y", Expression: "aws_x.y.attr", Path: ""},
+				{Address: "data.a.b", Expression: "data.a.b.c[0]", Path: ""},
+			},
+		},
+		"Nested values": {
+			value: cty.ObjectVal(map[string]cty.Value{
+				"list": cty.TupleVal([]cty.Value{*tokens.NewIdentValue("var.a"), cty.StringVal("b")}),
+				"obj":  tokens.NewOrderedObjectValue(tokens.NewObjectEntry("k", *tokens.NewIdentValue("local.c"))),
+			}),
+			expected: []analysis.Reference{
+				{Address: "var.a", Expression: "var.a", Path: ""},
+				{Address: "local.c", Expression: "local.c", Path: ""},
+			},
+		},
+		"For expression": {
+			value: *tokens.NewExpressionValue("[for s in var.subnets : s.id]"),
+			expected: []analysis.Reference{
+				{Address: "var.subnets", Expression: "var.subnets", Path: ""},
+			},
+		},
+		"Incomplete references": {
+			value:    *tokens.NewExpressionValue(`[var, data.a, aws_x, var["x"], path.module]`),
+			expected: []analysis.Reference{},
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				actual := analysis.ExtractReferences(tcase.value)
+				if len(actual) == 0 && len(tcase.expected) == 0 {
+					return
+				}
+
+				if !reflect.DeepEqual(actual, tcase.expected) {
+					t.Errorf("Case \"%s\": expected %#v, got %#v", tcname, tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestBlockReferences(t *testing.T) {
+	t.Parallel()
+
+	content := tfsig.NewSignature("content")
+	content.AppendAttribute("name", *tokens.NewIdentValue("setting.value.name"))
+	content.AppendAttribute("value", *tokens.NewIdentValue("var.value"))
+
+	dynamic := tfsig.NewSignature("dynamic", "setting")
+	dynamic.AppendAttribute("for_each", *tokens.NewIdentValue("setting.value"))
+	dynamic.AppendChild(content)
+
+	iteratorContent := tfsig.NewSignature("content")
+	iteratorContent.AppendAttribute("name", *tokens.NewIdentValue("it.value"))
+
+	iteratorDynamic := tfsig.NewSignature("dynamic", "other")
+	iteratorDynamic.AppendAttribute("for_each", *tokens.NewIdentValue("var.others"))
+	iteratorDynamic.AppendAttribute("iterator", *tokens.NewIdentValue("it"))
+	iteratorDynamic.AppendChild(iteratorContent)
+
+	sig := tfsig.NewResource("aws_x", "y")
+	sig.AppendAttribute("name", *tokens.NewIdentValue("var.name"))
+	sig.AppendChild(dynamic)
+	sig.AppendChild(iteratorDynamic)
+	sig.DependsOn([]string{"aws_z.w"})
+
+	expected := []analysis.Reference{
+		{Address: "var.name", Expression: "var.name", Path: `resource "aws_x" "y" > name`},
+		{
+			// Iterator is only in scope inside the content block
+			Address:    "setting.value",
+			Expression: "setting.value",
+			Path:       `resource "aws_x" "y" > dynamic "setting" > for_each`,
+		},
+		{
+			Address:    "var.value",
+			Expression: "var.value",
+			Path:       `resource "aws_x" "y" > dynamic "setting" > content > value`,
+		},
+		{
+			Address:    "var.others",
+			Expression: "var.others",
+			Path:       `resource "aws_x" "y" > dynamic "other" > for_each`,
+		},
+		{Address: "aws_z.w", Expression: "aws_z.w", Path: `resource "aws_x" "y" > depends_on`},
+	}
+
+	if actual := analysis.BlockReferences(sig); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+}
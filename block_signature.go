@@ -6,20 +6,26 @@ It provides ability to generate block signature which are way easier to manipula
 package tfsig
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/yoanm/go-tfsig/tokens"
 )
 
+const indentSize = 2
+
 /** Public **/
 
 // NewSignature returns a BlockSignature pointer filled with provided type and labels.
 func NewSignature(name string, labels ...string) *BlockSignature {
 	return &BlockSignature{
-		typeName: name,
-		labels:   labels,
-		elements: BodyElements{},
+		typeName:   name,
+		labels:     labels,
+		elements:   BodyElements{},
+		formatOpts: nil,
 	}
 }
 
@@ -31,9 +37,10 @@ func NewResource(name, id string, labels ...string) *BlockSignature {
 // BlockSignature is basically a wrapper to HCL blocks
 // It holds a type, the block labels and its elements.
 type BlockSignature struct {
-	typeName string
-	labels   []string
-	elements BodyElements
+	typeName   string
+	labels     []string
+	elements   BodyElements
+	formatOpts []tokens.GenerateOption
 }
 
 // GetType returns the type of the block.
@@ -46,6 +53,16 @@ func (sig *BlockSignature) GetLabels() []string {
 	return sig.labels
 }
 
+// GetHeader returns the block type followed by its quoted labels (e.g. `resource "res_name" "res_id"`).
+func (sig *BlockSignature) GetHeader() string {
+	header := sig.GetType()
+	for _, label := range sig.GetLabels() {
+		header += " " + strconv.Quote(label)
+	}
+
+	return header
+}
+
 // GetElements returns all elements attached to the block.
 func (sig *BlockSignature) GetElements() BodyElements {
 	return sig.elements
@@ -62,8 +79,29 @@ func (sig *BlockSignature) AppendElement(element BodyElement) {
 }
 
 // AppendAttribute appends an attribute to the block.
-func (sig *BlockSignature) AppendAttribute(name string, value cty.Value) {
-	sig.AppendElement(NewBodyAttribute(name, value))
+//
+// Provided options are used to render the attribute value (see `tokens.GenerateOption`).
+func (sig *BlockSignature) AppendAttribute(name string, value cty.Value, opts ...tokens.GenerateOption) {
+	sig.AppendElement(NewBodyAttribute(name, value, opts...))
+}
+
+// SetAttribute replaces the value of an existing attribute in place, or appends the attribute if it doesn't exist.
+//
+// Format options of the existing attribute are kept if no option is provided.
+func (sig *BlockSignature) SetAttribute(name string, value cty.Value, opts ...tokens.GenerateOption) {
+	for idx, elem := range sig.elements {
+		if elem.IsBodyAttribute() && elem.GetName() == name {
+			if len(opts) == 0 {
+				opts = elem.GetFormatOptions()
+			}
+
+			sig.elements[idx] = NewBodyAttribute(name, value, opts...)
+
+			return
+		}
+	}
+
+	sig.AppendAttribute(name, value, opts...)
 }
 
 // AppendChild appends a child block to the block.
@@ -76,13 +114,35 @@ func (sig *BlockSignature) AppendEmptyLine() {
 	sig.AppendElement(NewBodyEmptyLine())
 }
 
-// Build creates a `hclwrite.Block` and appends block's elements to it.
+// SetFormatOptions defines options used to render all attribute values of the block and its children
+// (see `tokens.GenerateOption`)
+//
+// Options attached to an attribute are applied after them.
+func (sig *BlockSignature) SetFormatOptions(opts ...tokens.GenerateOption) {
+	sig.formatOpts = opts
+}
+
+// Build creates a `hclwrite.Block` and appends block's elements to it
+//
+// It panics if an attribute value contains an unknown value (see `SafeBuild()` for a version returning an error).
 func (sig *BlockSignature) Build() *hclwrite.Block {
-	block := hclwrite.NewBlock(sig.GetType(), sig.GetLabels())
+	return sig.build(nil, 0)
+}
 
-	sig.writeElementsToBody(block.Body())
+// SafeBuild is like `Build()`, except that it returns an error instead of panicking if an attribute value
+// can't be rendered (see `Validate()`).
+func (sig *BlockSignature) SafeBuild() (*hclwrite.Block, error) {
+	if err := sig.Validate(); err != nil {
+		return nil, err
+	}
 
-	return block
+	return sig.Build(), nil
+}
+
+// BuildRedacted creates a `hclwrite.Block` like `Build()`, except that values marked as Sensitive
+// are rendered as `"(sensitive)"` placeholders (e.g. for logs or previews).
+func (sig *BlockSignature) BuildRedacted() *hclwrite.Block {
+	return sig.build([]tokens.GenerateOption{tokens.WithRedaction()}, 0)
 }
 
 // BuildTokens builds the block signature as `hclwrite.Tokens`.
@@ -100,22 +160,74 @@ func (sig *BlockSignature) BuildTokens() hclwrite.Tokens {
 
 /** Private **/
 
-// writeElementsToBody writes all block signature elements to the provided `hclwrite.Body`
+// build creates the `hclwrite.Block` located at the provided depth, inherited format options are applied first.
+func (sig *BlockSignature) build(inheritedOpts []tokens.GenerateOption, depth int) *hclwrite.Block {
+	block := hclwrite.NewBlock(sig.GetType(), sig.GetLabels())
+
+	writeElementsToBody(block.Body(), sig.GetElements(), mergeFormatOptions(inheritedOpts, sig.formatOpts), depth+1)
+
+	return block
+}
+
+// writeElementsToBody writes all provided elements to the provided `hclwrite.Body` located at the provided depth
 //
 // It takes care of attribute values containing `hclwrite.Tokens` encapsulated into a cty capsule.
-func (sig *BlockSignature) writeElementsToBody(body *hclwrite.Body) {
-	for _, value := range sig.GetElements() {
+func writeElementsToBody(body *hclwrite.Body, elements BodyElements, opts []tokens.GenerateOption, depth int) {
+	for _, value := range elements {
 		switch {
 		case value.IsBodyBlock():
-			body.AppendBlock(value.Build())
+			body.AppendBlock(value.GetBodyBlock().build(opts, depth))
 		case value.IsBodyAttribute():
-			if tokens.ContainsCapsule(value.attr) {
-				body.SetAttributeRaw(value.GetName(), tokens.Generate(value.attr))
-			} else {
-				body.SetAttributeValue(value.GetName(), *value.attr)
-			}
+			writeAttributeToBody(body, value.GetName(), value.attr, mergeFormatOptions(opts, value.formatOpts), depth)
 		case value.IsBodyEmptyLine():
 			body.AppendNewline()
 		}
 	}
 }
+
+// writeAttributeToBody sets the attribute on the provided `hclwrite.Body` located at the provided depth
+// (it takes care of capsules and format options).
+func writeAttributeToBody(
+	body *hclwrite.Body,
+	name string,
+	value *cty.Value,
+	opts []tokens.GenerateOption,
+	depth int,
+) {
+	if unmarked, _ := value.UnmarkDeep(); !unmarked.IsWhollyKnown() {
+		if err := tokens.ValidateValue(unmarked); err != nil {
+			panic(fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	// hclwrite doesn't manage marked values
+	if len(opts) > 0 || tokens.ContainsCapsule(value) || value.ContainsMarked() {
+		body.SetAttributeRaw(name, generateAttributeTokens(name, value, opts, depth))
+
+		return
+	}
+
+	body.SetAttributeValue(name, *value)
+}
+
+// generateAttributeTokens converts the attribute value to `hclwrite.Tokens`, format options are applied
+// taking into account the attribute position.
+func generateAttributeTokens(name string, value *cty.Value, opts []tokens.GenerateOption, depth int) hclwrite.Tokens {
+	if len(opts) == 0 {
+		return tokens.Generate(value)
+	}
+
+	lineIndent := depth * indentSize
+	// Attribute value starts after `name = `
+	opts = append([]tokens.GenerateOption{tokens.WithIndent(lineIndent, lineIndent+len(name)+len(" = "))}, opts...)
+
+	return tokens.Generate(value, opts...)
+}
+
+func mergeFormatOptions(inherited, opts []tokens.GenerateOption) []tokens.GenerateOption {
+	if len(opts) == 0 {
+		return inherited
+	}
+
+	return append(append([]tokens.GenerateOption{}, inherited...), opts...)
+}
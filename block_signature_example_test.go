@@ -117,3 +117,26 @@ func Example_reorder() {
 	//   attribute5 = "value5"
 	// }
 }
+
+func ExampleBlockSignature_BuildRedacted() {
+	sig := tfsig.NewResource("res_name", "res_id")
+	sig.AppendAttribute("username", cty.StringVal("admin"))
+	sig.AppendAttribute("password", cty.StringVal("secret").Mark(tfsig.Sensitive))
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(sig.Build())
+	hclFile.Body().AppendNewline()
+	hclFile.Body().AppendBlock(sig.BuildRedacted())
+
+	fmt.Println(string(hclFile.Bytes()))
+	// Output:
+	// resource "res_name" "res_id" {
+	//   username = "admin"
+	//   password = "secret"
+	// }
+	//
+	// resource "res_name" "res_id" {
+	//   username = "admin"
+	//   password = "(sensitive)"
+	// }
+}
@@ -88,12 +88,17 @@ func appendLifecycleConditionBlock(lifecycleSig *BlockSignature, name string, lc
 		return
 	}
 
+	lifecycleSig.AppendChild(newConditionBlock(name, *tokens.NewIdentValue(lcCond.Condition), lcCond.ErrorMessage))
+}
+
+// newConditionBlock returns a condition block (e.g. `precondition` or `assert`) with provided condition and message.
+func newConditionBlock(name string, condition cty.Value, errorMessage string) *BlockSignature {
 	cond := NewSignature(name)
 
-	cond.AppendAttribute("condition", *tokens.NewIdentValue(lcCond.Condition))
-	cond.AppendAttribute("error_message", cty.StringVal(lcCond.ErrorMessage))
+	cond.AppendAttribute("condition", condition)
+	cond.AppendAttribute("error_message", cty.StringVal(errorMessage))
 
-	lifecycleSig.AppendChild(cond)
+	return cond
 }
 
 func appendLifecycleBoolAttribute(lifecycleSig *BlockSignature, name string, value *bool) {
@@ -64,6 +64,32 @@ func TestNewEmptyResource(t *testing.T) {
 	}
 }
 
+func TestBlockSignature_GetHeader(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    *tfsig.BlockSignature
+		expected string
+	}{
+		"No label":     {tfsig.NewSignature("locals"), "locals"},
+		"Labels":       {tfsig.NewResource("res_name", "res_id"), `resource "res_name" "res_id"`},
+		"Quoted label": {tfsig.NewSignature("variable", `my "var"`), `variable "my \"var\""`},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := tcase.value.GetHeader(); actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected %q, got %q", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
+
 func TestBlockSignature_BuildTokens(t *testing.T) {
 	t.Parallel()
 
@@ -99,3 +125,32 @@ func TestBlockSignature_BuildTokens(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestBlockSignature_SetAttribute(t *testing.T) {
+	t.Parallel()
+
+	sig := tfsig.NewSignature("block")
+	sig.AppendAttribute("attr1", cty.StringVal("old"), tokens.WithMultiLine())
+	sig.AppendAttribute("attr2", cty.StringVal("value2"))
+	sig.SetAttribute("attr1", cty.StringVal("new"))
+	sig.SetAttribute("attr3", cty.StringVal("value3"))
+
+	elements := sig.GetElements()
+	if len(elements) != 3 {
+		t.Fatalf("expected 3 elements, got %d", len(elements))
+	}
+
+	for idx, expected := range []string{"attr1", "attr2", "attr3"} {
+		if elements[idx].GetName() != expected {
+			t.Errorf("Case \"%d\": expected %s, got %s", idx, expected, elements[idx].GetName())
+		}
+	}
+
+	if !elements[0].GetBodyAttribute().RawEquals(cty.StringVal("new")) {
+		t.Errorf("expected new value, got %#v", *elements[0].GetBodyAttribute())
+	}
+
+	if len(elements[0].GetFormatOptions()) != 1 {
+		t.Errorf("expected format options to be kept, got %d option(s)", len(elements[0].GetFormatOptions()))
+	}
+}
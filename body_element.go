@@ -3,21 +3,25 @@ package tfsig
 import (
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
 )
 
 // NewBodyBlock returns a Block BodyElement.
 func NewBodyBlock(block *BlockSignature) BodyElement {
-	return BodyElement{name: block.GetType(), block: block, isEmptyLine: false, attr: nil}
+	return BodyElement{name: block.GetType(), block: block, isEmptyLine: false, attr: nil, formatOpts: nil}
 }
 
-// NewBodyAttribute returns an Attribute BodyElement.
-func NewBodyAttribute(name string, attr cty.Value) BodyElement {
-	return BodyElement{name: name, attr: &attr, isEmptyLine: false, block: nil}
+// NewBodyAttribute returns an Attribute BodyElement
+//
+// Provided options are used to render the attribute value (see `tokens.GenerateOption`).
+func NewBodyAttribute(name string, attr cty.Value, opts ...tokens.GenerateOption) BodyElement {
+	return BodyElement{name: name, attr: &attr, isEmptyLine: false, block: nil, formatOpts: opts}
 }
 
 // NewBodyEmptyLine returns an empty line BodyElement.
 func NewBodyEmptyLine() BodyElement {
-	return BodyElement{name: "empty_line", isEmptyLine: true, block: nil, attr: nil}
+	return BodyElement{name: "empty_line", isEmptyLine: true, block: nil, attr: nil, formatOpts: nil}
 }
 
 // BodyElement is a wrapper for more or less anything that can be appended to a BlockSignature.
@@ -26,6 +30,7 @@ type BodyElement struct {
 	block       *BlockSignature
 	attr        *cty.Value
 	isEmptyLine bool
+	formatOpts  []tokens.GenerateOption
 }
 
 // BodyElements is a simple wrapper for a list of BodyElement.
@@ -83,3 +88,8 @@ func (e BodyElement) Build() *hclwrite.Block {
 
 	return e.block.Build()
 }
+
+// GetFormatOptions returns options used to render the attribute value (see `tokens.GenerateOption`).
+func (e BodyElement) GetFormatOptions() []tokens.GenerateOption {
+	return e.formatOpts
+}
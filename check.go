@@ -0,0 +1,94 @@
+package tfsig
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	dataBlockType   = "data"
+	assertBlockType = "assert"
+	// A data block is labelled with its type and its name.
+	dataBlockLabelCount = 2
+)
+
+// ErrInvalidCheck is returned by `NewCheck()` and `ValidateCheck()` when a `check` block contains more than one
+// nested `data` block, when the nested block is not a valid `data` block or when there is no `assert` block.
+var ErrInvalidCheck = errors.New("invalid check block")
+
+// NewCheck returns a BlockSignature pointer for a terraform `check` block with provided name
+//
+// The scoped data source is rendered first (not rendered if nil), followed by an `assert` block for each assertion,
+// in the provided order.
+// It returns an error if the provided data source is not a `data` block with a type and a name labels,
+// or if no assertion is provided.
+func NewCheck(name string, data *BlockSignature, asserts ...Assertion) (*BlockSignature, error) {
+	sig := NewSignature("check", name)
+
+	if data != nil {
+		if data.GetType() != dataBlockType {
+			return nil, fmt.Errorf("%w %q: expected a data block, got %q", ErrInvalidCheck, name, data.GetType())
+		}
+
+		sig.AppendChild(data)
+	}
+
+	for _, assertion := range asserts {
+		appendSeparatedChild(sig, newAssertionBlock(assertion))
+	}
+
+	if err := ValidateCheck(sig); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// ValidateCheck returns an error if the provided `check` block signature contains more than one nested `data` block,
+// if the nested `data` block doesn't have exactly two labels (e.g. `data "http" "example"`)
+// or if it doesn't contain any `assert` block.
+//
+// It is useful when children have been appended after `NewCheck()` call.
+func ValidateCheck(sig *BlockSignature) error {
+	count, assertCount := 0, 0
+
+	for _, elem := range sig.GetElements() {
+		if elem.IsBodyBlock() && elem.GetBodyBlock().GetType() == assertBlockType {
+			assertCount++
+		}
+
+		if !elem.IsBodyBlock() || elem.GetBodyBlock().GetType() != dataBlockType {
+			continue
+		}
+
+		count++
+		if count > 1 {
+			return fmt.Errorf("%w %q: only one nested data block is allowed", ErrInvalidCheck, checkName(sig))
+		}
+
+		if labels := elem.GetBodyBlock().GetLabels(); len(labels) != dataBlockLabelCount {
+			return fmt.Errorf(
+				"%w %q: nested data block must have a type and a name labels, got %d label(s)",
+				ErrInvalidCheck,
+				checkName(sig),
+				len(labels),
+			)
+		}
+	}
+
+	if assertCount == 0 {
+		return fmt.Errorf("%w %q: at least one assert block is required", ErrInvalidCheck, checkName(sig))
+	}
+
+	return nil
+}
+
+/** Private **/
+
+func checkName(sig *BlockSignature) string {
+	if labels := sig.GetLabels(); len(labels) > 0 {
+		return labels[0]
+	}
+
+	return ""
+}
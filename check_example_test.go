@@ -0,0 +1,42 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func ExampleNewCheck() {
+	data := tfsig.NewSignature("data", "http", "health")
+	data.AppendAttribute("url", cty.StringVal("https://example.com/health"))
+
+	sig, err := tfsig.NewCheck(
+		"health_check",
+		data,
+		tfsig.Assertion{
+			Condition:    "data.http.health.status_code==200",
+			ErrorMessage: "Service is unhealthy",
+		},
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(sig.Build())
+	fmt.Println(string(hclFile.Bytes()))
+	// Output:
+	// check "health_check" {
+	//   data "http" "health" {
+	//     url = "https://example.com/health"
+	//   }
+	//
+	//   assert {
+	//     condition     = data.http.health.status_code == 200
+	//     error_message = "Service is unhealthy"
+	//   }
+	// }
+}
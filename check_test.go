@@ -0,0 +1,93 @@
+package tfsig_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func TestNewCheck(t *testing.T) {
+	t.Parallel()
+
+	assertion := tfsig.Assertion{Condition: "true", ErrorMessage: "message"}
+	cases := map[string]struct {
+		data     *tfsig.BlockSignature
+		asserts  []tfsig.Assertion
+		expected string
+	}{
+		"Without data source": {
+			data:     nil,
+			asserts:  []tfsig.Assertion{assertion},
+			expected: "",
+		},
+		"Data source": {
+			data:     tfsig.NewSignature("data", "http", "example"),
+			asserts:  []tfsig.Assertion{assertion, assertion},
+			expected: "",
+		},
+		"Without assertion": {
+			data:     tfsig.NewSignature("data", "http", "example"),
+			asserts:  nil,
+			expected: `invalid check block "name": at least one assert block is required`,
+		},
+		"Not a data block": {
+			data:     tfsig.NewResource("aws_s3_bucket", "this"),
+			asserts:  []tfsig.Assertion{assertion},
+			expected: `invalid check block "name": expected a data block, got "resource"`,
+		},
+		"Data block without name": {
+			data:     tfsig.NewSignature("data", "http"),
+			asserts:  []tfsig.Assertion{assertion},
+			expected: `invalid check block "name": nested data block must have a type and a name labels, got 1 label(s)`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				sig, err := tfsig.NewCheck("name", tcase.data, tcase.asserts...)
+				if tcase.expected == "" {
+					if err != nil {
+						t.Fatalf("Case \"%s\": unexpected error %v", t.Name(), err)
+					}
+
+					if sig == nil || sig.GetType() != "check" {
+						t.Errorf("Case \"%s\": expected a check block, got %v", t.Name(), sig)
+					}
+
+					return
+				}
+
+				if !errors.Is(err, tfsig.ErrInvalidCheck) || err.Error() != tcase.expected {
+					t.Errorf("Case \"%s\": expected error %q, got %v", t.Name(), tcase.expected, err)
+				}
+			},
+		)
+	}
+}
+
+func TestValidateCheck(t *testing.T) {
+	t.Parallel()
+
+	assertion := tfsig.Assertion{Condition: "true", ErrorMessage: "message"}
+
+	sig, err := tfsig.NewCheck("name", tfsig.NewSignature("data", "http", "first"), assertion)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err = tfsig.ValidateCheck(sig); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	sig.AppendChild(tfsig.NewSignature("data", "http", "second"))
+
+	expected := `invalid check block "name": only one nested data block is allowed`
+	if err = tfsig.ValidateCheck(sig); !errors.Is(err, tfsig.ErrInvalidCheck) || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Package gen implements the tfsig-gen command, which generates Go packages with typed builders from a provider schema
+document.
+*/
+package gen
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yoanm/go-tfsig/schema"
+)
+
+var (
+	// ErrMissingSchema is returned when `-schema` flag is not provided.
+	ErrMissingSchema = errors.New("-schema flag is required")
+	// ErrUnknownProvider is returned when the provider provided with `-provider` flag doesn't exist in the schema.
+	ErrUnknownProvider = errors.New("unknown provider")
+)
+
+// Run parses provided command line arguments and generates provider packages accordingly
+//
+// Usage and flag parsing errors are written to the provided output.
+func Run(args []string, output io.Writer) error {
+	flags := flag.NewFlagSet("tfsig-gen", flag.ContinueOnError)
+	flags.SetOutput(output)
+
+	schemaPath := flags.String("schema", "", "path to the `terraform providers schema -json` output (required)")
+	outDir := flags.String("out", ".", "directory where provider packages are generated")
+	provider := flags.String("provider", "", "only generate the provided provider (local name or full address)")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	if *schemaPath == "" {
+		return ErrMissingSchema
+	}
+
+	doc, err := schema.Load(*schemaPath)
+	if err != nil {
+		return fmt.Errorf("unable to load schema: %w", err)
+	}
+
+	return generate(doc, *outDir, *provider)
+}
+
+/** Private **/
+
+// generate writes a package for each provider of the document, or only for the provided one if not empty.
+func generate(doc *schema.ProvidersSchema, outDir, provider string) error {
+	generated := 0
+
+	for _, address := range slices.Sorted(maps.Keys(doc.ProviderSchemas)) {
+		localName := address[strings.LastIndex(address, "/")+1:]
+		if provider != "" && provider != address && provider != localName {
+			continue
+		}
+
+		files, err := GenerateProvider(PackageName(localName), address, doc.ProviderSchemas[address])
+		if err != nil {
+			return fmt.Errorf("%s: %w", address, err)
+		}
+
+		if err = writeFiles(filepath.Join(outDir, PackageName(localName)), files); err != nil {
+			return fmt.Errorf("%s: %w", address, err)
+		}
+
+		generated++
+	}
+
+	if provider != "" && generated == 0 {
+		return fmt.Errorf("%w %q", ErrUnknownProvider, provider)
+	}
+
+	return nil
+}
+
+func writeFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		//nolint:gosec // Generated sources are not sensitive
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filepath.Join(dir, name), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package gen_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yoanm/go-tfsig/cmd/tfsig-gen/internal/gen"
+	"github.com/yoanm/go-tfsig/schema"
+)
+
+const testSchemaPath = "testdata/providers.schema.json"
+
+func parseProvider(t *testing.T, resourceSchemas string) *schema.ProviderSchema {
+	t.Helper()
+
+	content := `{"provider_schemas": {"hashicorp/aws": {"resource_schemas": ` + resourceSchemas + `}}}`
+
+	doc, err := schema.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	return doc.ProviderSchemas["hashicorp/aws"]
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+
+	err := gen.Run([]string{"-schema", testSchemaPath, "-out", outDir, "-provider", "aws"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	goldenFiles, err := filepath.Glob("testdata/aws/*.golden")
+	if err != nil || len(goldenFiles) == 0 {
+		t.Fatalf("unable to find golden files: %v", err)
+	}
+
+	for _, goldenFile := range goldenFiles {
+		var expected, actual []byte
+
+		if expected, err = os.ReadFile(goldenFile); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		name := filepath.Base(goldenFile[:len(goldenFile)-len(".golden")])
+
+		if actual, err = os.ReadFile(filepath.Join(outDir, "aws", name)); err != nil {
+			t.Errorf("Case \"%s\": %v", name, err)
+
+			continue
+		}
+
+		if !bytes.Equal(expected, actual) {
+			t.Errorf("Case \"%s\": expected\n%s\ngot\n%s", name, expected, actual)
+		}
+	}
+
+	if _, err = os.Stat(filepath.Join(outDir, "googlebeta")); !os.IsNotExist(err) {
+		t.Errorf("expected googlebeta package to be filtered out, got %v", err)
+	}
+}
+
+func TestRun_allProviders(t *testing.T) {
+	t.Parallel()
+
+	outDir := t.TempDir()
+	if err := gen.Run([]string{"-schema", testSchemaPath, "-out", outDir}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, path := range []string{"aws/resource_aws_s3_bucket.go", "googlebeta/doc.go"} {
+		if _, err := os.Stat(filepath.Join(outDir, path)); err != nil {
+			t.Errorf("Case \"%s\": %v", path, err)
+		}
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args     []string
+		expected error
+	}{
+		"Missing schema": {
+			args:     []string{},
+			expected: gen.ErrMissingSchema,
+		},
+		"Unknown provider": {
+			args:     []string{"-schema", testSchemaPath, "-provider", "azurerm"},
+			expected: gen.ErrUnknownProvider,
+		},
+		"Invalid schema": {
+			args:     []string{"-schema", "testdata/aws/doc.go.golden"},
+			expected: schema.ErrInvalidSchema,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				args := append([]string{"-out", t.TempDir()}, tcase.args...)
+				if err := gen.Run(args, &bytes.Buffer{}); !errors.Is(err, tcase.expected) {
+					t.Errorf("Case \"%s\": expected %v, got %v", tcname, tcase.expected, err)
+				}
+			},
+		)
+	}
+}
+
+func TestGenerateProvider_duplicateTypeName(t *testing.T) {
+	t.Parallel()
+
+	provider := parseProvider(t, `{
+		"aws_s3_bucket": {"block": {}},
+		"aws_s3-bucket": {"block": {}},
+		"aws_s3_bucket_website": {"block": {}}
+	}`)
+
+	if _, err := gen.GenerateProvider("aws", "hashicorp/aws", provider); !errors.Is(err, gen.ErrDuplicateTypeName) {
+		t.Errorf("expected %v, got %v", gen.ErrDuplicateTypeName, err)
+	}
+}
+
+func TestGenerateProvider_typedSetterConflict(t *testing.T) {
+	t.Parallel()
+
+	provider := parseProvider(t, `{
+		"aws_x": {"block": {"attributes": {
+			"name": {"type": "string", "optional": true},
+			"name_string": {"type": "string", "optional": true}
+		}}}
+	}`)
+
+	files, err := gen.GenerateProvider("aws", "hashicorp/aws", provider)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	content := string(files["resource_aws_x.go"])
+	if strings.Count(content, "func (b *AwsX) SetNameString(") != 1 {
+		t.Errorf("expected a single SetNameString method, got\n%s", content)
+	}
+
+	if !strings.Contains(content, "func (b *AwsX) SetNameStringString(value string)") {
+		t.Errorf("expected a typed setter for name_string attribute, got\n%s", content)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"aws_s3_bucket":  "AwsS3Bucket",
+		"instance_id":    "InstanceID",
+		"http-endpoint":  "HTTPEndpoint",
+		"3d_secure_mode": "X3dSecureMode",
+	}
+
+	for name, expected := range cases {
+		if actual := gen.GoName(name); actual != expected {
+			t.Errorf("Case \"%s\": expected %s, got %s", name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,421 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/schema"
+)
+
+// ErrDuplicateTypeName is returned when two builders would be generated with the same Go type name.
+var ErrDuplicateTypeName = errors.New("duplicate type name")
+
+//nolint:gochecknoglobals // Better to keep it as **internal** global var than define it each time
+var (
+	// initialisms are rendered upper-cased in Go names (e.g. `instance_id` => `InstanceID`).
+	initialisms = map[string]bool{
+		"ACL": true, "API": true, "ARN": true, "CPU": true, "DNS": true, "HTTP": true, "HTTPS": true, "ID": true,
+		"IP": true, "JSON": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+		"URI": true, "URL": true, "UUID": true, "VM": true, "XML": true,
+	}
+	fileTemplate = template.Must(template.New("file").Parse(fileTemplateContent))
+	docTemplate  = template.Must(template.New("doc").Parse(docTemplateContent))
+)
+
+const generatedHeader = "// Code generated by tfsig-gen. DO NOT EDIT.\n\n"
+
+const docTemplateContent = generatedHeader +
+	"// Package {{.Package}} provides typed builders for {{.Address}} resources and data sources.\n" +
+	"package {{.Package}}\n"
+
+const fileTemplateContent = generatedHeader + `package {{.Package}}
+
+import (
+{{- if .UsesCty}}
+	"github.com/zclconf/go-cty/cty"
+{{end}}
+	"github.com/yoanm/go-tfsig"
+)
+{{range $b := .Builders}}
+// {{$b.TypeName}} is a builder for {{$b.Kind}}.
+{{- if $b.Description}}
+//
+{{- range $b.Description}}
+//{{if .}} {{.}}{{end}}
+{{- end}}
+{{- end}}
+type {{$b.TypeName}} struct {
+	sig *tfsig.BlockSignature
+}
+
+// New{{$b.TypeName}} returns a new builder for {{$b.Kind}}{{$b.ConstructorDoc}}.
+func New{{$b.TypeName}}({{$b.ConstructorArgs}}) *{{$b.TypeName}} {
+	return &{{$b.TypeName}}{sig: {{$b.SignatureExpr}}}
+}
+
+// Signature returns the underlying BlockSignature.
+func (b *{{$b.TypeName}}) Signature() *tfsig.BlockSignature {
+	return b.sig
+}
+{{range $b.Attributes}}
+// Set{{.Method}} sets ` + "`{{.Name}}`" + ` attribute ({{.Details}}).
+{{- if .Description}}
+//
+{{- range .Description}}
+//{{if .}} {{.}}{{end}}
+{{- end}}
+{{- end}}
+{{- if .Deprecated}}
+//
+// Deprecated: ` + "`{{.Name}}`" + ` attribute is deprecated by the provider.
+{{- end}}
+func (b *{{$b.TypeName}}) Set{{.Method}}(value cty.Value) *{{$b.TypeName}} {
+	b.sig.SetAttribute("{{.Name}}", value)
+
+	return b
+}
+{{- if .TypedSuffix}}
+
+// Set{{.Method}}{{.TypedSuffix}} sets ` + "`{{.Name}}`" + ` attribute with a literal {{.GoType}} value` +
+	` (see ` + "`Set{{.Method}}()`" + `).
+{{- if .Deprecated}}
+//
+// Deprecated: ` + "`{{.Name}}`" + ` attribute is deprecated by the provider.
+{{- end}}
+func (b *{{$b.TypeName}}) Set{{.Method}}{{.TypedSuffix}}(value {{.GoType}}) *{{$b.TypeName}} {
+	return b.Set{{.Method}}({{.CtyExpr}})
+}
+{{- end}}
+{{end}}
+{{- range $b.Blocks}}
+// Append{{.Method}} appends a nested ` + "`{{.Name}}`" + ` block ({{.Details}}).
+{{- if .Deprecated}}
+//
+// Deprecated: ` + "`{{.Name}}`" + ` block is deprecated by the provider.
+{{- end}}
+func (b *{{$b.TypeName}}) Append{{.Method}}(block *{{.TypeName}}) *{{$b.TypeName}} {
+	b.sig.AppendChild(block.Signature())
+
+	return b
+}
+{{end}}
+{{- end}}`
+
+// GenerateProvider returns the content of each generated file of the provider package, keyed by file name.
+func GenerateProvider(pkg, address string, provider *schema.ProviderSchema) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	typeNames := map[string]string{}
+
+	content, err := render(docTemplate, struct{ Package, Address string }{Package: pkg, Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("doc.go: %w", err)
+	}
+
+	files["doc.go"] = content
+
+	kinds := []struct {
+		prefix, typePrefix, kind, sigExpr string
+		schemas                           map[string]*schema.Schema
+	}{
+		{"resource_", "", "resource", "tfsig.NewResource(%q, name)", provider.ResourceSchemas},
+		{"data_", "Data", "data source", `tfsig.NewSignature("data", %q, name)`, provider.DataSourceSchemas},
+	}
+
+	for _, kind := range kinds {
+		for _, blockType := range slices.Sorted(maps.Keys(kind.schemas)) {
+			if kind.schemas[blockType] == nil || kind.schemas[blockType].Block == nil {
+				continue
+			}
+
+			root := &builderModel{
+				TypeName:        kind.typePrefix + GoName(blockType),
+				Kind:            fmt.Sprintf("`%s` %s", blockType, kind.kind),
+				ConstructorArgs: "name string",
+				ConstructorDoc:  " with the provided name",
+				SignatureExpr:   fmt.Sprintf(kind.sigExpr, blockType),
+				Description:     nil,
+				Attributes:      nil,
+				Blocks:          nil,
+			}
+
+			model := &fileModel{Package: pkg, UsesCty: false, Builders: nil}
+			if err = addBuilders(model, root, kind.schemas[blockType].Block, typeNames, blockType); err != nil {
+				return nil, err
+			}
+
+			if files[kind.prefix+blockType+".go"], err = render(fileTemplate, model); err != nil {
+				return nil, fmt.Errorf("%s: %w", blockType, err)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// GoName converts a terraform name to an exported Go name (e.g. `aws_s3_bucket` => `AwsS3Bucket`).
+func GoName(name string) string {
+	result := ""
+
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if upper := strings.ToUpper(part); initialisms[upper] {
+			result += upper
+
+			continue
+		}
+
+		result += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	if result == "" || !unicode.IsLetter(rune(result[0])) {
+		result = "X" + result
+	}
+
+	return result
+}
+
+// PackageName converts a provider local name to a Go package name (e.g. `google-beta` => `googlebeta`).
+func PackageName(localName string) string {
+	return strings.Map(
+		func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+
+			return -1
+		},
+		localName,
+	)
+}
+
+/** Private **/
+
+type fileModel struct {
+	Package  string
+	UsesCty  bool
+	Builders []*builderModel
+}
+
+type builderModel struct {
+	TypeName        string
+	Kind            string
+	Description     []string
+	ConstructorArgs string
+	ConstructorDoc  string
+	SignatureExpr   string
+	Attributes      []attributeModel
+	Blocks          []blockModel
+}
+
+type attributeModel struct {
+	Name        string
+	Method      string
+	Details     string
+	Description []string
+	Deprecated  bool
+	// TypedSuffix, GoType and CtyExpr describe the typed setter of primitive attributes (none if TypedSuffix is empty)
+	TypedSuffix string
+	GoType      string
+	CtyExpr     string
+}
+
+type blockModel struct {
+	Name       string
+	Method     string
+	TypeName   string
+	Details    string
+	Deprecated bool
+}
+
+// addBuilders appends to the model the builder of the provided block, followed by builders of its nested blocks.
+func addBuilders(
+	model *fileModel,
+	builder *builderModel,
+	block *schema.Block,
+	typeNames map[string]string,
+	origin string,
+) error {
+	if existing, exists := typeNames[builder.TypeName]; exists {
+		return fmt.Errorf("%w %s for %s and %s", ErrDuplicateTypeName, builder.TypeName, existing, origin)
+	}
+
+	typeNames[builder.TypeName] = origin
+	builder.Description = descriptionLines(block.Description)
+	model.Builders = append(model.Builders, builder)
+
+	addAttributes(model, builder, block)
+
+	nestedBuilders := []*builderModel{}
+
+	for _, name := range slices.Sorted(maps.Keys(block.BlockTypes)) {
+		nested := block.BlockTypes[name]
+		if nested.Block == nil {
+			continue
+		}
+
+		nestedBuilder := newNestedBuilder(builder, name, nested)
+		nestedBuilders = append(nestedBuilders, nestedBuilder)
+
+		builder.Blocks = append(builder.Blocks, blockModel{
+			Name:       name,
+			Method:     GoName(name),
+			TypeName:   nestedBuilder.TypeName,
+			Details:    blockDetails(nested),
+			Deprecated: nested.Block.Deprecated,
+		})
+	}
+
+	for idx, nestedBuilder := range nestedBuilders {
+		nestedBlock := block.BlockTypes[builder.Blocks[idx].Name].Block
+		if err := addBuilders(model, nestedBuilder, nestedBlock, typeNames, origin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAttributes appends to the builder a setter for each non-computed-only attribute of the block.
+func addAttributes(model *fileModel, builder *builderModel, block *schema.Block) {
+	methods := map[string]bool{}
+
+	for _, name := range slices.Sorted(maps.Keys(block.Attributes)) {
+		attr := block.Attributes[name]
+		if attr.Computed && !attr.Optional && !attr.Required {
+			continue
+		}
+
+		model.UsesCty = true
+		methods[GoName(name)] = true
+
+		builder.Attributes = append(builder.Attributes, newAttributeModel(name, attr))
+	}
+
+	for idx, attr := range builder.Attributes {
+		// Typed setter would conflict with the setter of another attribute (e.g. `foo_string` for `foo`)
+		if attr.TypedSuffix != "" && methods[attr.Method+attr.TypedSuffix] {
+			builder.Attributes[idx].TypedSuffix = ""
+		}
+	}
+}
+
+// newNestedBuilder returns the builder of a nested block of the provided parent builder.
+func newNestedBuilder(parent *builderModel, name string, nested *schema.NestedBlock) *builderModel {
+	builder := &builderModel{
+		TypeName:        strings.TrimSuffix(parent.TypeName, "Block") + GoName(name) + "Block",
+		Kind:            fmt.Sprintf("`%s` nested block", name),
+		ConstructorArgs: "",
+		ConstructorDoc:  "",
+		SignatureExpr:   fmt.Sprintf("tfsig.NewSignature(%q)", name),
+		Description:     nil,
+		Attributes:      nil,
+		Blocks:          nil,
+	}
+
+	if nested.NestingMode == schema.NestingMap {
+		builder.ConstructorArgs = "key string"
+		builder.ConstructorDoc = " with the provided map key"
+		builder.SignatureExpr = fmt.Sprintf("tfsig.NewSignature(%q, key)", name)
+	}
+
+	return builder
+}
+
+// newAttributeModel returns the model of the attribute, with a typed setter for string, number and bool attributes
+// (values of sensitive attributes are marked as Sensitive).
+func newAttributeModel(name string, attr *schema.Attribute) attributeModel {
+	model := attributeModel{
+		Name:        name,
+		Method:      GoName(name),
+		Details:     attributeDetails(attr),
+		Description: descriptionLines(attr.Description),
+		Deprecated:  attr.Deprecated,
+		TypedSuffix: "",
+		GoType:      "",
+		CtyExpr:     "",
+	}
+
+	switch attr.ImpliedType() {
+	case cty.String:
+		model.TypedSuffix, model.GoType, model.CtyExpr = "String", "string", "cty.StringVal(value)"
+	case cty.Number:
+		model.TypedSuffix, model.GoType, model.CtyExpr = "Number", "float64", "cty.NumberFloatVal(value)"
+	case cty.Bool:
+		model.TypedSuffix, model.GoType, model.CtyExpr = "Bool", "bool", "cty.BoolVal(value)"
+	}
+
+	if model.TypedSuffix != "" && attr.Sensitive {
+		model.CtyExpr += ".Mark(tfsig.Sensitive)"
+	}
+
+	return model
+}
+
+func render(tmpl *template.Template, data any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated %s source: %w", tmpl.Name(), err)
+	}
+
+	return content, nil
+}
+
+func attributeDetails(attr *schema.Attribute) string {
+	details := typeexpr.TypeString(attr.ImpliedType())
+
+	switch {
+	case attr.Required:
+		details += ", required"
+	case attr.Computed:
+		details += ", optional, computed"
+	default:
+		details += ", optional"
+	}
+
+	if attr.Sensitive {
+		details += ", sensitive"
+	}
+
+	return details
+}
+
+func blockDetails(nested *schema.NestedBlock) string {
+	details := string(nested.NestingMode)
+	if nested.MinItems > 0 {
+		details += fmt.Sprintf(", at least %d", nested.MinItems)
+	}
+
+	if nested.MaxItems > 0 {
+		details += fmt.Sprintf(", at most %d", nested.MaxItems)
+	}
+
+	return details
+}
+
+func descriptionLines(description string) []string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil
+	}
+
+	lines := strings.Split(description, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+
+	return lines
+}
@@ -0,0 +1,30 @@
+/*
+Command tfsig-gen generates Go packages with typed builders from a provider schema document
+
+Usage:
+
+	terraform providers schema -json > schema.json
+	tfsig-gen -schema schema.json -out ./providers [-provider aws]
+
+A package named after the provider local name (e.g. `aws`) is generated for each provider, with a file for each
+resource (`resource_<type>.go`) and each data source (`data_<type>.go`).
+Each builder has a setter for each non-computed-only attribute, an `Append` method for each nested block type and
+a `Signature()` method returning the underlying `*tfsig.BlockSignature` (e.g. to add meta-arguments like `count`).
+Setters take a `cty.Value` in order to accept expressions (e.g. `tokens.NewIdentValue("var.x")`), string, number and
+bool attributes also have a typed setter for literal values (e.g. `SetBucketString("my-bucket")`).
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/yoanm/go-tfsig/cmd/tfsig-gen/internal/gen"
+)
+
+func main() {
+	if err := gen.Run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
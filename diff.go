@@ -0,0 +1,339 @@
+package tfsig
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// ChangeKind describes the kind of change detected by `Diff()`.
+type ChangeKind string
+
+const (
+	// AttributeAdded means the attribute exists only in the new signature.
+	AttributeAdded ChangeKind = "attribute_added"
+	// AttributeRemoved means the attribute exists only in the old signature.
+	AttributeRemoved ChangeKind = "attribute_removed"
+	// AttributeModified means the attribute exists in both signatures but with a different value.
+	AttributeModified ChangeKind = "attribute_modified"
+	// BlockAdded means the nested block exists only in the new signature.
+	BlockAdded ChangeKind = "block_added"
+	// BlockRemoved means the nested block exists only in the old signature.
+	BlockRemoved ChangeKind = "block_removed"
+	// BlockMoved means the nested block exists in both signatures but at a different position.
+	BlockMoved ChangeKind = "block_moved"
+	// LabelsModified means the block type is the same but its labels are different.
+	LabelsModified ChangeKind = "labels_modified"
+)
+
+// Change is a single difference between two signatures
+//
+// Path contains the header of each block (e.g. `resource "res_name" "res_id"`) from the root signature
+// down to the block holding the changed element.
+// Before and After hold the element as it was in the old and in the new signature (nil when it does not exist).
+type Change struct {
+	Kind   ChangeKind
+	Path   []string
+	Before *BodyElement
+	After  *BodyElement
+}
+
+// Diff returns the semantic differences between the old signature `before` and the new signature `after`
+//
+// Attributes are matched by name, their position and empty lines are ignored.
+// Attribute values are compared semantically by cty, values containing capsules are compared
+// by their rendered bytes.
+// Nested blocks are matched by type and labels (in appearance order when several blocks share the same
+// type and labels), remaining blocks with the same type are then considered as blocks with modified labels.
+func Diff(before, after *BlockSignature) []Change {
+	changes := []Change{}
+
+	if before == nil && after == nil {
+		return changes
+	}
+
+	if before == nil || after == nil {
+		return append(changes, newBlockChange(nil, before, after))
+	}
+
+	if before.GetType() != after.GetType() {
+		// Not the same block at all
+		return append(changes, newBlockChange(nil, before, nil), newBlockChange(nil, nil, after))
+	}
+
+	if !slices.Equal(before.GetLabels(), after.GetLabels()) {
+		changes = append(changes, newLabelsChange(nil, before, after))
+	}
+
+	return diffBlocks(changes, []string{after.GetHeader()}, before, after)
+}
+
+// FormatChanges renders the provided changes as a unified-text report
+//
+// Changes are grouped by path, each group starts with a `@@ <path> @@` header followed by
+// removed lines (prefixed by `-`), added lines (prefixed by `+`) and moved blocks (prefixed by `~`).
+// Provided options are used to render values, use `tokens.WithRedaction()` to hide values marked as Sensitive
+// (e.g. for logs).
+func FormatChanges(changes []Change, opts ...tokens.GenerateOption) string {
+	var (
+		buf      strings.Builder
+		lastPath string
+	)
+
+	for _, change := range changes {
+		if path := strings.Join(change.Path, " > "); path != lastPath || buf.Len() == 0 {
+			buf.WriteString("@@ " + path + " @@\n")
+
+			lastPath = path
+		}
+
+		switch change.Kind {
+		case BlockMoved:
+			writePrefixedLines(&buf, "~ ", renderElementHeader(*change.After)+" (moved)")
+		case LabelsModified:
+			writePrefixedLines(&buf, "- ", renderElementHeader(*change.Before))
+			writePrefixedLines(&buf, "+ ", renderElementHeader(*change.After))
+		case AttributeAdded, AttributeRemoved, AttributeModified, BlockAdded, BlockRemoved:
+			if change.Before != nil {
+				writePrefixedLines(&buf, "- ", renderElement(*change.Before, opts))
+			}
+
+			if change.After != nil {
+				writePrefixedLines(&buf, "+ ", renderElement(*change.After, opts))
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// ValuesEqual returns true if both values are semantically equal
+//
+// Values containing special capsules encapsulating `hclwrite.Tokens` are compared by their rendered bytes.
+// Marks (e.g. Sensitive) are ignored.
+func ValuesEqual(left, right cty.Value) bool {
+	left, _ = left.UnmarkDeep()
+	right, _ = right.UnmarkDeep()
+
+	if tokens.ContainsCapsule(&left) || tokens.ContainsCapsule(&right) || !left.Type().Equals(right.Type()) {
+		return bytes.Equal(renderValue(left), renderValue(right))
+	}
+
+	if !left.IsWhollyKnown() || !right.IsWhollyKnown() {
+		return left.RawEquals(right)
+	}
+
+	return left.Equals(right).True()
+}
+
+/** Private **/
+
+func diffBlocks(changes []Change, path []string, a, b *BlockSignature) []Change {
+	changes = diffAttributes(changes, path, a.GetElements(), b.GetElements())
+
+	return diffChildren(changes, path, a.GetElements(), b.GetElements())
+}
+
+func diffAttributes(changes []Change, path []string, aElems, bElems BodyElements) []Change {
+	aAttrs := map[string]BodyElement{}
+
+	for _, elem := range aElems {
+		if elem.IsBodyAttribute() {
+			aAttrs[elem.GetName()] = elem
+		}
+	}
+
+	bNames := map[string]bool{}
+
+	for _, elem := range bElems {
+		if !elem.IsBodyAttribute() {
+			continue
+		}
+
+		bNames[elem.GetName()] = true
+
+		after := elem
+
+		before, exists := aAttrs[elem.GetName()]
+
+		switch {
+		case !exists:
+			changes = append(changes, Change{Kind: AttributeAdded, Path: path, Before: nil, After: &after})
+		case !ValuesEqual(*before.GetBodyAttribute(), *after.GetBodyAttribute()):
+			changes = append(changes, Change{Kind: AttributeModified, Path: path, Before: &before, After: &after})
+		}
+	}
+
+	for _, elem := range aElems {
+		if elem.IsBodyAttribute() && !bNames[elem.GetName()] {
+			before := elem
+			changes = append(changes, Change{Kind: AttributeRemoved, Path: path, Before: &before, After: nil})
+		}
+	}
+
+	return changes
+}
+
+func diffChildren(changes []Change, path []string, aElems, bElems BodyElements) []Change {
+	aBlocks, bBlocks := childBlocks(aElems), childBlocks(bElems)
+	pairs, aLeft, bLeft := matchBlocks(aBlocks, bBlocks, (*BlockSignature).GetHeader)
+
+	// Blocks left with the same type => labels have been modified
+	labelPairs, aLeft, bLeft := matchBlocks(aLeft, bLeft, (*BlockSignature).GetType)
+
+	for _, child := range aLeft {
+		changes = append(changes, newBlockChange(path, child, nil))
+	}
+
+	for _, child := range bLeft {
+		changes = append(changes, newBlockChange(path, nil, child))
+	}
+
+	for _, pair := range labelPairs {
+		changes = append(changes, newLabelsChange(path, pair[0], pair[1]))
+	}
+
+	allPairs := slices.Concat(pairs, labelPairs)
+	changes = append(changes, detectMovedBlocks(path, allPairs, aBlocks, bBlocks)...)
+
+	for _, pair := range allPairs {
+		changes = diffBlocks(changes, append(append([]string{}, path...), pair[1].GetHeader()), pair[0], pair[1])
+	}
+
+	return changes
+}
+
+// matchBlocks pairs blocks having the same key, in appearance order.
+func matchBlocks(aBlocks, bBlocks []*BlockSignature, keyFn func(*BlockSignature) string) (
+	/* pairs */ [][2]*BlockSignature,
+	/* aLeft */ []*BlockSignature,
+	/* bLeft */ []*BlockSignature,
+) {
+	pairs := [][2]*BlockSignature{}
+	aLeft := []*BlockSignature{}
+	bLeft := []*BlockSignature{}
+	used := make([]bool, len(bBlocks))
+
+	for _, aBlock := range aBlocks {
+		found := false
+
+		for idx, bBlock := range bBlocks {
+			if !used[idx] && keyFn(aBlock) == keyFn(bBlock) {
+				pairs = append(pairs, [2]*BlockSignature{aBlock, bBlock})
+				used[idx], found = true, true
+
+				break
+			}
+		}
+
+		if !found {
+			aLeft = append(aLeft, aBlock)
+		}
+	}
+
+	for idx, bBlock := range bBlocks {
+		if !used[idx] {
+			bLeft = append(bLeft, bBlock)
+		}
+	}
+
+	return pairs, aLeft, bLeft
+}
+
+// detectMovedBlocks reports matched blocks which don't have the same position among matched blocks.
+func detectMovedBlocks(path []string, pairs [][2]*BlockSignature, aBlocks, bBlocks []*BlockSignature) []Change {
+	changes := []Change{}
+	aOrder, bOrder := matchedOrder(aBlocks, pairs, 0), matchedOrder(bBlocks, pairs, 1)
+
+	for _, pair := range pairs {
+		if aOrder[pair[0]] != bOrder[pair[1]] {
+			before, after := NewBodyBlock(pair[0]), NewBodyBlock(pair[1])
+			changes = append(changes, Change{Kind: BlockMoved, Path: path, Before: &before, After: &after})
+		}
+	}
+
+	return changes
+}
+
+func matchedOrder(blocks []*BlockSignature, pairs [][2]*BlockSignature, side int) map[*BlockSignature]int {
+	matched := map[*BlockSignature]bool{}
+	for _, pair := range pairs {
+		matched[pair[side]] = true
+	}
+
+	order := map[*BlockSignature]int{}
+
+	for _, block := range blocks {
+		if matched[block] {
+			order[block] = len(order)
+		}
+	}
+
+	return order
+}
+
+func childBlocks(elems BodyElements) []*BlockSignature {
+	blocks := []*BlockSignature{}
+
+	for _, elem := range elems {
+		if elem.IsBodyBlock() {
+			blocks = append(blocks, elem.GetBodyBlock())
+		}
+	}
+
+	return blocks
+}
+
+func newBlockChange(path []string, before, after *BlockSignature) Change {
+	change := Change{Kind: BlockAdded, Path: path, Before: nil, After: nil}
+
+	if before != nil {
+		elem := NewBodyBlock(before)
+		change.Kind, change.Before = BlockRemoved, &elem
+	}
+
+	if after != nil {
+		elem := NewBodyBlock(after)
+		change.After = &elem
+	}
+
+	return change
+}
+
+func newLabelsChange(path []string, before, after *BlockSignature) Change {
+	beforeElem, afterElem := NewBodyBlock(before), NewBodyBlock(after)
+
+	return Change{Kind: LabelsModified, Path: path, Before: &beforeElem, After: &afterElem}
+}
+
+func renderValue(val cty.Value, opts ...tokens.GenerateOption) []byte {
+	return hclwrite.Format(tokens.Generate(&val, opts...).Bytes())
+}
+
+func renderElementHeader(elem BodyElement) string {
+	return elem.GetBodyBlock().GetHeader()
+}
+
+func renderElement(elem BodyElement, opts []tokens.GenerateOption) string {
+	if elem.IsBodyBlock() {
+		hclFile := hclwrite.NewEmptyFile()
+		hclFile.Body().AppendBlock(elem.GetBodyBlock().build(opts, 0))
+
+		return string(hclFile.Bytes())
+	}
+
+	value := renderValue(*elem.GetBodyAttribute(), mergeFormatOptions(opts, elem.formatOpts)...)
+
+	return string(hclwrite.Format([]byte(elem.GetName() + " = " + string(value))))
+}
+
+func writePrefixedLines(buf *strings.Builder, prefix, content string) {
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		buf.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+	}
+}
@@ -0,0 +1,37 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func ExampleDiff() {
+	before := tfsig.NewResource("res_name", "res_id")
+	before.AppendAttribute("attribute1", cty.StringVal("value1"))
+	before.AppendAttribute("attribute2", cty.NumberIntVal(2))
+
+	beforeChild := tfsig.NewSignature("block1")
+	beforeChild.AppendAttribute("attribute11", *tokens.NewIdentValue("var.foo"))
+	before.AppendChild(beforeChild)
+
+	after := tfsig.NewResource("res_name", "res_id")
+	after.AppendAttribute("attribute1", cty.StringVal("value1"))
+	after.AppendAttribute("attribute3", cty.BoolVal(true))
+
+	afterChild := tfsig.NewSignature("block1")
+	afterChild.AppendAttribute("attribute11", *tokens.NewIdentValue("var.bar"))
+	after.AppendChild(afterChild)
+
+	fmt.Print(tfsig.FormatChanges(tfsig.Diff(before, after)))
+	// Output:
+	// @@ resource "res_name" "res_id" @@
+	// + attribute3 = true
+	// - attribute2 = 2
+	// @@ resource "res_name" "res_id" > block1 @@
+	// - attribute11 = var.foo
+	// + attribute11 = var.bar
+}
@@ -0,0 +1,201 @@
+package tfsig_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	newSig := func(attrs map[string]cty.Value, children ...*tfsig.BlockSignature) *tfsig.BlockSignature {
+		sig := tfsig.NewResource("res_name", "res_id")
+
+		for _, name := range []string{"attr1", "attr2", "attr3"} {
+			if val, ok := attrs[name]; ok {
+				sig.AppendAttribute(name, val)
+			}
+		}
+
+		for _, child := range children {
+			sig.AppendChild(child)
+		}
+
+		return sig
+	}
+	newChild := func(name string, labels ...string) *tfsig.BlockSignature {
+		sig := tfsig.NewSignature(name, labels...)
+		sig.AppendAttribute("attr", cty.StringVal(name))
+
+		return sig
+	}
+
+	cases := map[string]struct {
+		before   *tfsig.BlockSignature
+		after    *tfsig.BlockSignature
+		expected []tfsig.ChangeKind
+	}{
+		"Nil signatures":    {nil, nil, []tfsig.ChangeKind{}},
+		"Added signature":   {nil, newSig(nil), []tfsig.ChangeKind{tfsig.BlockAdded}},
+		"Removed signature": {newSig(nil), nil, []tfsig.ChangeKind{tfsig.BlockRemoved}},
+		"Different type": {
+			newSig(nil),
+			tfsig.NewSignature("data"),
+			[]tfsig.ChangeKind{tfsig.BlockRemoved, tfsig.BlockAdded},
+		},
+		"Different labels": {
+			newSig(nil),
+			tfsig.NewResource("res_name", "another_id"),
+			[]tfsig.ChangeKind{tfsig.LabelsModified},
+		},
+		"Same": {
+			newSig(map[string]cty.Value{"attr1": cty.StringVal("A")}, newChild("block1")),
+			newSig(map[string]cty.Value{"attr1": cty.StringVal("A")}, newChild("block1")),
+			[]tfsig.ChangeKind{},
+		},
+		"Semantically equal values": {
+			newSig(map[string]cty.Value{
+				"attr1": cty.NumberIntVal(3),
+				"attr2": cty.SetVal([]cty.Value{cty.StringVal("A"), cty.StringVal("B")}),
+				"attr3": *tokens.NewIdentValue("var.foo"),
+			}),
+			newSig(map[string]cty.Value{
+				"attr1": cty.NumberFloatVal(3.0),
+				"attr2": cty.SetVal([]cty.Value{cty.StringVal("B"), cty.StringVal("A")}),
+				"attr3": *tokens.NewIdentValue("var.foo"),
+			}),
+			[]tfsig.ChangeKind{},
+		},
+		"Attributes": {
+			newSig(map[string]cty.Value{"attr1": cty.StringVal("A"), "attr2": *tokens.NewIdentValue("var.foo")}),
+			newSig(map[string]cty.Value{"attr2": *tokens.NewIdentValue("var.bar"), "attr3": cty.True}),
+			[]tfsig.ChangeKind{tfsig.AttributeModified, tfsig.AttributeAdded, tfsig.AttributeRemoved},
+		},
+		"Added and removed blocks": {
+			newSig(nil, newChild("block1")),
+			newSig(nil, newChild("block2")),
+			[]tfsig.ChangeKind{tfsig.BlockRemoved, tfsig.BlockAdded},
+		},
+		"Nested block labels": {
+			newSig(nil, newChild("block1", "A")),
+			newSig(nil, newChild("block1", "B")),
+			[]tfsig.ChangeKind{tfsig.LabelsModified},
+		},
+		"Moved blocks": {
+			newSig(nil, newChild("block1"), newChild("block2"), newChild("block3")),
+			newSig(nil, newChild("block2"), newChild("block1"), newChild("block3")),
+			[]tfsig.ChangeKind{tfsig.BlockMoved, tfsig.BlockMoved},
+		},
+		"New block in the middle": {
+			newSig(nil, newChild("block1"), newChild("block3")),
+			newSig(nil, newChild("block1"), newChild("block2"), newChild("block3")),
+			[]tfsig.ChangeKind{tfsig.BlockAdded},
+		},
+		"Nested attributes": {
+			newSig(nil, newChild("block1")),
+			newSig(nil, tfsig.NewSignature("block1")),
+			[]tfsig.ChangeKind{tfsig.AttributeRemoved},
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				actual := []tfsig.ChangeKind{}
+				for _, change := range tfsig.Diff(tcase.before, tcase.after) {
+					actual = append(actual, change.Kind)
+				}
+
+				if !reflect.DeepEqual(tcase.expected, actual) {
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestFormatChanges(t *testing.T) {
+	t.Parallel()
+
+	before := tfsig.NewResource("res_name", "res_id")
+	before.AppendChild(tfsig.NewSignature("block1", "A"))
+	before.AppendChild(tfsig.NewSignature("block2"))
+	before.AppendChild(tfsig.NewSignature("block3"))
+
+	after := tfsig.NewResource("res_name", "res_id")
+	after.AppendChild(tfsig.NewSignature("block3"))
+	after.AppendChild(tfsig.NewSignature("block1", "B"))
+
+	newBlock := tfsig.NewSignature("block4")
+	newBlock.AppendAttribute("attr", cty.ListVal([]cty.Value{cty.StringVal("A"), cty.StringVal("B")}))
+	after.AppendChild(newBlock)
+
+	expected := `@@ resource "res_name" "res_id" @@
+- block2 {
+- }
++ block4 {
++   attr = ["A", "B"]
++ }
+- block1 "A"
++ block1 "B"
+~ block3 (moved)
+~ block1 "B" (moved)
+`
+
+	if actual := tfsig.FormatChanges(tfsig.Diff(before, after)); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestFormatChanges_sensitive(t *testing.T) {
+	t.Parallel()
+
+	before := tfsig.NewSignature("sig")
+	before.AppendAttribute("unchanged", cty.StringVal("same").Mark(tfsig.Sensitive))
+	before.AppendAttribute("password", cty.StringVal("old").Mark(tfsig.Sensitive))
+
+	after := tfsig.NewSignature("sig")
+	after.AppendAttribute("unchanged", cty.StringVal("same").Mark(tfsig.Sensitive))
+	after.AppendAttribute("password", cty.StringVal("new").Mark(tfsig.Sensitive))
+
+	cases := map[string]struct {
+		opts     []tokens.GenerateOption
+		expected string
+	}{
+		"Default": {
+			opts: nil,
+			expected: `@@ sig @@
+- password = "old"
++ password = "new"
+`,
+		},
+		"Redacted": {
+			opts: []tokens.GenerateOption{tokens.WithRedaction()},
+			expected: `@@ sig @@
+- password = "(sensitive)"
++ password = "(sensitive)"
+`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := tfsig.FormatChanges(tfsig.Diff(before, after), tcase.opts...); actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected:\n%s\ngot:\n%s", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,143 @@
+package tfsig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/andreyvit/diff"
+)
+
+const defaultFileMode fs.FileMode = 0o644
+
+// FileDrift describes the difference between the generated content of a file and its content on disk.
+type FileDrift struct {
+	Path string
+	// Diff is a line diff between the content on disk (`-` lines) and the generated content (`+` lines)
+	Diff string
+}
+
+// Check compares the generated content with the content of the provided file
+//
+// It returns nil if both are identical. A file which doesn't exist is reported as drifted.
+func (f *FileSignature) Check(path string) (*FileDrift, error) {
+	actual, err := readFileIfExists(path)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := f.SafeBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", path, err)
+	}
+
+	if actual != nil && bytes.Equal(actual, expected) {
+		return nil, nil //nolint:nilnil // No drift is not an error
+	}
+
+	return &FileDrift{Path: path, Diff: diff.LineDiff(string(actual), string(expected))}, nil
+}
+
+// CheckDir checks each provided file (key is the filename relative to the provided directory)
+// and returns drifted ones, sorted by path.
+//
+// Like `gofmt -l`, it can be used in CI in order to fail when committed files differ from generated ones.
+func CheckDir(dir string, files map[string]*FileSignature) ([]FileDrift, error) {
+	drifts := []FileDrift{}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		drift, err := files[name].Check(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	return drifts, nil
+}
+
+// WriteFile writes the generated content to the provided path, only if content actually changes
+//
+// Write is atomic: content is written to a temporary file in the same directory which is then renamed.
+// It returns true if the file has been written.
+func (f *FileSignature) WriteFile(path string) (bool, error) {
+	actual, err := readFileIfExists(path)
+	if err != nil {
+		return false, err
+	}
+
+	content, err := f.SafeBytes()
+	if err != nil {
+		return false, fmt.Errorf("failed to render %s: %w", path, err)
+	}
+
+	if actual != nil && bytes.Equal(actual, content) {
+		return false, nil
+	}
+
+	if err = writeFileAtomic(path, content); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+/** Private **/
+
+func readFileIfExists(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return content, nil
+}
+
+// writeFileAtomic writes content to a temporary file which is then renamed
+// (permissions of the existing file are kept).
+func writeFileAtomic(path string, content []byte) error {
+	mode := defaultFileMode
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+
+	tmpPath := tmpFile.Name()
+
+	_, err = tmpFile.Write(content)
+	if err == nil {
+		err = tmpFile.Chmod(mode)
+	}
+
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath)
+
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package tfsig_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func newDriftFile(value string) *tfsig.FileSignature {
+	sig := tfsig.NewResource("res_name", "res_id")
+	sig.AppendAttribute("attribute1", cty.StringVal(value))
+
+	return tfsig.NewFileSignature(sig)
+}
+
+func TestCheckDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for name, value := range map[string]string{"same.tf": "value1", "drifted.tf": "old"} {
+		if err := os.WriteFile(filepath.Join(dir, name), newDriftFile(value).Bytes(), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "empty.tf"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	drifts, err := tfsig.CheckDir(dir, map[string]*tfsig.FileSignature{
+		"same.tf":          newDriftFile("value1"),
+		"drifted.tf":       newDriftFile("new"),
+		"missing.tf":       newDriftFile("value1"),
+		"empty.tf":         tfsig.NewFileSignature(),
+		"missing_empty.tf": tfsig.NewFileSignature(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := []tfsig.FileDrift{
+		{
+			Path: filepath.Join(dir, "drifted.tf"),
+			Diff: ` resource "res_name" "res_id" {
+-  attribute1 = "old"
++  attribute1 = "new"
+ }`,
+		},
+		{
+			Path: filepath.Join(dir, "missing.tf"),
+			Diff: `+resource "res_name" "res_id" {
++  attribute1 = "value1"
++}`,
+		},
+		{
+			Path: filepath.Join(dir, "missing_empty.tf"),
+			Diff: "",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, drifts) {
+		t.Errorf("expected %#v, got %#v", expected, drifts)
+	}
+}
+
+func TestFileSignature_WriteFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "main.tf")
+	file := newDriftFile("value1")
+
+	for idx, expected := range []bool{true, false} {
+		written, err := file.WriteFile(path)
+		if err != nil {
+			t.Fatalf("write #%d: unexpected error %v", idx, err)
+		} else if written != expected {
+			t.Errorf("write #%d: expected %v, got %v", idx, expected, written)
+		}
+	}
+
+	if drift, err := file.Check(path); err != nil || drift != nil {
+		t.Errorf("expected no drift, got %v (error: %v)", drift, err)
+	}
+
+	if written, err := newDriftFile("value2").WriteFile(path); err != nil || !written {
+		t.Errorf("expected file to be written, got %v (error: %v)", written, err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only one file, got %d", len(entries))
+	}
+}
+
+func TestFileSignature_Check_error(t *testing.T) {
+	t.Parallel()
+
+	// Reading a directory fails
+	if _, err := newDriftFile("value1").Check(t.TempDir()); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,70 @@
+package tfsig
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+//nolint:gochecknoglobals // Better to keep it as **internal** global var than define it each time
+var defaultExpressionRootNames = []string{
+	"local",
+	"var",
+	"data",
+	"module",
+	"each",
+	"count",
+	"self",
+	"path",
+	"terraform",
+}
+
+// NewExpressionTokenMatcher returns an instance of ExpressionTokenMatcher with provided list of root names
+// to consider as valid references (e.g. resource types like `aws_s3_bucket`)
+//
+// `local`, `var`, `data`, `module`, `each`, `count`, `self`, `path` and `terraform` root names are allowed by default.
+func NewExpressionTokenMatcher(rootNames ...string) ExpressionTokenMatcher {
+	roots := map[string]bool{}
+	for _, name := range append(rootNames, defaultExpressionRootNames...) {
+		roots[name] = true
+	}
+
+	return ExpressionTokenMatcher{rootNames: roots}
+}
+
+// ExpressionTokenMatcher is an implementation for IdentTokenMatcherInterface relying on HCL parser
+//
+// A string is considered as 'ident' token only if it is a valid HCL expression which references at least one variable,
+// and if all referenced variables have an allowed root name followed by at least one attribute or index.
+// E.g. `var.x is cool`, `10-20`, `"var.x"` or `terraform` are not 'ident' tokens, `lower(var.x)` or `module.vpc.id`
+// are.
+type ExpressionTokenMatcher struct {
+	rootNames map[string]bool
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m ExpressionTokenMatcher) IsIdentToken(s string) bool {
+	expr, diags := hclsyntax.ParseExpression([]byte(s), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return false
+	}
+
+	switch expr.(type) {
+	case *hclsyntax.TemplateExpr, *hclsyntax.LiteralValueExpr:
+		// Plain text or literal values
+		return false
+	}
+
+	variables := expr.Variables()
+	if len(variables) == 0 {
+		return false
+	}
+
+	for _, traversal := range variables {
+		// Bare root names (e.g. `self` or `terraform`) are plain words
+		if len(traversal) < 2 || !m.rootNames[traversal.RootName()] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,32 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func ExampleNewExpressionTokenMatcher() {
+	text := "var.x is cool"
+	moduleOutput := "module.vpc.id"
+	resourceAttr := "aws_s3_bucket.b.arn"
+
+	valGen := tfsig.NewValueGeneratorWith(tfsig.NewExpressionTokenMatcher("aws_s3_bucket"))
+	sig := tfsig.NewSignature("my_block")
+	sig.AppendAttribute("attr1", *valGen.ToString(&text))
+	sig.AppendAttribute("attr2", *valGen.ToString(&moduleOutput))
+	sig.AppendAttribute("attr3", *valGen.ToString(&resourceAttr))
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(sig.Build())
+	fmt.Println(string(hclFile.Bytes()))
+
+	// Output:
+	// my_block {
+	//   attr1 = "var.x is cool"
+	//   attr2 = module.vpc.id
+	//   attr3 = aws_s3_bucket.b.arn
+	// }
+}
@@ -0,0 +1,65 @@
+package tfsig_test
+
+import (
+	"testing"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func TestExpressionTokenMatcher_IsIdentToken(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		expected bool
+	}{
+		"Plain text":                {"hello world", false},
+		"Single word":               {"hello", false},
+		"Text starting with var.":   {"var.x is cool", false},
+		"Quoted string":             {`"var.x"`, false},
+		"Number":                    {"12", false},
+		"Boolean":                   {"true", false},
+		"Range":                     {"10-20", false},
+		"Function without variable": {"max(1, 2)", false},
+		"Local":                     {"local.my_local", true},
+		"Variable":                  {"var.my_var", true},
+		"Data source":               {"data.my_data.my_property", true},
+		"Module output":             {"module.vpc.id", true},
+		"Each":                      {"each.value.name", true},
+		"Count":                     {"count.index", true},
+		"Path":                      {"path.module", true},
+		"Index":                     {"data.x.y[0].id", true},
+		"Function":                  {"lower(var.name)", true},
+		"Conditional":               {`var.enabled ? 1 : 0`, true},
+		"Template":                  {`"${var.prefix}-name"`, false},
+		"Unknown resource type":     {"aws_s3_bucket.b.arn", false},
+		"Known resource type":       {"aws_iam_role.r.arn", true},
+		"Unknown root in function":  {"lower(aws_s3_bucket.b.arn)", false},
+		"Bare terraform":            {"terraform", false},
+		"Bare self":                 {"self", false},
+		"Bare path":                 {"path", false},
+		"Bare count":                {"count", false},
+		"Bare each":                 {"each", false},
+		"Bare var":                  {"var", false},
+		"Bare data":                 {"data", false},
+		"Bare local":                {"local", false},
+		"Bare module":               {"module", false},
+		"Bare root in function":     {"lower(var)", false},
+		"Bare known resource type":  {"aws_iam_role", false},
+	}
+
+	matcher := tfsig.NewExpressionTokenMatcher("aws_iam_role")
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := matcher.IsIdentToken(tcase.value); actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
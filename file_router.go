@@ -0,0 +1,76 @@
+package tfsig
+
+// DefaultMainFilename is the filename used by default for blocks which are not routed to a specific file.
+const DefaultMainFilename = "main.tf"
+
+// FileRouter is a simple interface declaring required method to decide in which file a block must be written.
+type FileRouter interface {
+	// Route returns the filename for the provided block, or an empty string if router doesn't handle the block.
+	Route(block *BlockSignature) string
+}
+
+// FileRouterFunc is an adapter to allow the use of ordinary functions as FileRouter.
+type FileRouterFunc func(block *BlockSignature) string
+
+// Route is the implementation for FileRouter.
+func (fn FileRouterFunc) Route(block *BlockSignature) string {
+	return fn(block)
+}
+
+// NewDefaultFileRouter returns a FileRouter implementing the usual terraform module layout:
+// `terraform` and `provider` blocks go to `versions.tf`, `variable` blocks go to `variables.tf`, `output` blocks go
+// to `outputs.tf` and everything else goes to `main.tf`.
+func NewDefaultFileRouter() FileRouterFunc {
+	return RouteByType(
+		map[string]string{
+			"terraform": "versions.tf",
+			"provider":  "versions.tf",
+			"variable":  "variables.tf",
+			"output":    "outputs.tf",
+		},
+		DefaultMainFilename,
+	)
+}
+
+// RouteByType returns a FileRouter routing blocks based on their type
+//
+// Blocks with a type not defined in provided routes are routed to fallback filename, or are not handled
+// if fallback is empty (see `ChainRouters()`).
+func RouteByType(routes map[string]string, fallback string) FileRouterFunc {
+	return FileRouterFunc(func(block *BlockSignature) string {
+		if filename, ok := routes[block.GetType()]; ok {
+			return filename
+		}
+
+		return fallback
+	})
+}
+
+// RouteByLabel returns a FileRouter routing blocks based on their first label (e.g. the resource type)
+//
+// Blocks without label or with a label not defined in provided routes are not handled (see `ChainRouters()`).
+func RouteByLabel(routes map[string]string) FileRouterFunc {
+	return FileRouterFunc(func(block *BlockSignature) string {
+		if labels := block.GetLabels(); len(labels) > 0 {
+			return routes[labels[0]]
+		}
+
+		return ""
+	})
+}
+
+// ChainRouters returns a FileRouter using the first non-empty filename returned by provided routers
+//
+// A router handling every block (e.g. `NewDefaultFileRouter()` or `RouteByType()` with a fallback) must be
+// the last one, routers located after it are never reached.
+func ChainRouters(routers ...FileRouter) FileRouterFunc {
+	return FileRouterFunc(func(block *BlockSignature) string {
+		for _, router := range routers {
+			if filename := router.Route(block); filename != "" {
+				return filename
+			}
+		}
+
+		return ""
+	})
+}
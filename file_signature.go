@@ -0,0 +1,130 @@
+package tfsig
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// NewFileSignature returns a FileSignature pointer filled with provided blocks.
+func NewFileSignature(blocks ...*BlockSignature) *FileSignature {
+	file := &FileSignature{elements: BodyElements{}, formatOpts: nil}
+
+	for _, block := range blocks {
+		file.AppendBlock(block)
+	}
+
+	return file
+}
+
+// FileSignature is basically a wrapper to an HCL file
+// It holds the top-level elements of the file.
+type FileSignature struct {
+	elements   BodyElements
+	formatOpts []tokens.GenerateOption
+}
+
+// GetElements returns all elements attached to the file.
+func (f *FileSignature) GetElements() BodyElements {
+	return f.elements
+}
+
+// SetElements overrides existing elements by provided ones.
+func (f *FileSignature) SetElements(elements BodyElements) {
+	f.elements = elements
+}
+
+// AppendElement appends an element to the file.
+func (f *FileSignature) AppendElement(element BodyElement) {
+	f.elements = append(f.elements, element)
+}
+
+// AppendAttribute appends an attribute to the file.
+//
+// Provided options are used to render the attribute value (see `tokens.GenerateOption`).
+func (f *FileSignature) AppendAttribute(name string, value cty.Value, opts ...tokens.GenerateOption) {
+	f.AppendElement(NewBodyAttribute(name, value, opts...))
+}
+
+// AppendBlock appends a block to the file.
+// And in case there is existing elements, it prepends an empty line.
+//
+// Nil blocks are ignored.
+func (f *FileSignature) AppendBlock(block *BlockSignature) {
+	if block == nil {
+		return
+	}
+
+	if len(f.elements) > 0 {
+		f.AppendEmptyLine()
+	}
+
+	f.AppendElement(NewBodyBlock(block))
+}
+
+// AppendEmptyLine appends an empty line to the file.
+func (f *FileSignature) AppendEmptyLine() {
+	f.AppendElement(NewBodyEmptyLine())
+}
+
+// GetBlocks returns all top-level blocks attached to the file.
+func (f *FileSignature) GetBlocks() []*BlockSignature {
+	return childBlocks(f.elements)
+}
+
+// SetFormatOptions defines options used to render all attribute values of the file, blocks included
+// (see `tokens.GenerateOption`)
+//
+// Options set on a block or attached to an attribute are applied after them.
+func (f *FileSignature) SetFormatOptions(opts ...tokens.GenerateOption) {
+	f.formatOpts = opts
+}
+
+// Build creates a `hclwrite.File` and appends file's elements to it
+//
+// It panics if an attribute value contains an unknown value (see `SafeBuild()` for a version returning an error).
+func (f *FileSignature) Build() *hclwrite.File {
+	hclFile := hclwrite.NewEmptyFile()
+
+	writeElementsToBody(hclFile.Body(), f.GetElements(), f.formatOpts, 0)
+
+	return hclFile
+}
+
+// BuildRedacted creates a `hclwrite.File` like `Build()`, except that values marked as Sensitive
+// are rendered as `"(sensitive)"` placeholders (see `BlockSignature.BuildRedacted()`).
+func (f *FileSignature) BuildRedacted() *hclwrite.File {
+	hclFile := hclwrite.NewEmptyFile()
+
+	opts := mergeFormatOptions(f.formatOpts, []tokens.GenerateOption{tokens.WithRedaction()})
+
+	writeElementsToBody(hclFile.Body(), f.GetElements(), opts, 0)
+
+	return hclFile
+}
+
+// SafeBuild is like `Build()`, except that it returns an error instead of panicking if an attribute value
+// can't be rendered (see `Validate()`).
+func (f *FileSignature) SafeBuild() (*hclwrite.File, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	return f.Build(), nil
+}
+
+// Bytes returns the content of the file, it panics if the file can't be rendered (see `SafeBytes()`).
+func (f *FileSignature) Bytes() []byte {
+	return f.Build().Bytes()
+}
+
+// SafeBytes returns the content of the file, or an error if the file can't be rendered (see `SafeBuild()`).
+func (f *FileSignature) SafeBytes() ([]byte, error) {
+	hclFile, err := f.SafeBuild()
+	if err != nil {
+		return nil, err
+	}
+
+	return hclFile.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func ExampleFileSignature() {
+	res1 := tfsig.NewResource("res_name", "res_id1")
+	res1.AppendAttribute("attribute1", cty.StringVal("value1"))
+
+	res2 := tfsig.NewResource("res_name", "res_id2")
+	res2.AppendAttribute("attribute1", cty.StringVal("value2"))
+
+	file := tfsig.NewFileSignature(res1, res2)
+
+	fmt.Print(string(file.Bytes()))
+	// Output:
+	// resource "res_name" "res_id1" {
+	//   attribute1 = "value1"
+	// }
+	//
+	// resource "res_name" "res_id2" {
+	//   attribute1 = "value2"
+	// }
+}
+
+func ExampleFileSignature_SetFormatOptions() {
+	subnets := cty.ListVal([]cty.Value{
+		cty.StringVal("10.0.1.0/24"),
+		cty.StringVal("10.0.2.0/24"),
+		cty.StringVal("10.0.3.0/24"),
+	})
+
+	res := tfsig.NewResource("res_name", "res_id")
+	res.AppendAttribute("private_subnets", subnets)
+	res.AppendAttribute("public_subnets", subnets, tokens.WithMaxLineWidth(0))
+
+	nested := tfsig.NewSignature("nested")
+	nested.AppendAttribute("zones", cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}))
+	res.AppendChild(nested)
+
+	file := tfsig.NewFileSignature(res)
+	file.SetFormatOptions(tokens.WithMaxLineWidth(60))
+
+	fmt.Print(string(file.Bytes()))
+	// Output:
+	// resource "res_name" "res_id" {
+	//   private_subnets = [
+	//     "10.0.1.0/24",
+	//     "10.0.2.0/24",
+	//     "10.0.3.0/24",
+	//   ]
+	//   public_subnets = ["10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"]
+	//   nested {
+	//     zones = ["a", "b"]
+	//   }
+	// }
+}
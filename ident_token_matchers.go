@@ -0,0 +1,204 @@
+package tfsig
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	interpolationStart = "${"
+	interpolationEnd   = "}"
+)
+
+// IdentTokenExtractorInterface is an optional interface a matcher can implement in order to alter an 'ident' token
+// before it is rendered (e.g. to strip a marker).
+type IdentTokenExtractorInterface interface {
+	IdentTokenMatcherInterface
+	// ExtractIdentToken returns the 'ident' token to render for the provided string.
+	// It is called only if `IsIdentToken()` returned true for that string.
+	ExtractIdentToken(str string) string
+}
+
+// AnyOf returns a matcher considering a string as 'ident' token if at least one of provided matchers does.
+func AnyOf(matchers ...IdentTokenMatcherInterface) AnyOfTokenMatcher {
+	return AnyOfTokenMatcher{matchers: matchers}
+}
+
+// AnyOfTokenMatcher is an implementation for IdentTokenMatcherInterface combining several matchers (see `AnyOf()`).
+type AnyOfTokenMatcher struct {
+	matchers []IdentTokenMatcherInterface
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m AnyOfTokenMatcher) IsIdentToken(str string) bool {
+	for _, matcher := range m.matchers {
+		if matcher.IsIdentToken(str) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExtractIdentToken is the implementation for IdentTokenExtractorInterface
+//
+// It delegates the extraction to the first matching matcher.
+func (m AnyOfTokenMatcher) ExtractIdentToken(str string) string {
+	for _, matcher := range m.matchers {
+		if matcher.IsIdentToken(str) {
+			return extractIdentToken(matcher, str)
+		}
+	}
+
+	return str
+}
+
+// AllOf returns a matcher considering a string as 'ident' token only if all provided matchers do.
+func AllOf(matchers ...IdentTokenMatcherInterface) AllOfTokenMatcher {
+	return AllOfTokenMatcher{matchers: matchers}
+}
+
+// AllOfTokenMatcher is an implementation for IdentTokenMatcherInterface combining several matchers (see `AllOf()`).
+type AllOfTokenMatcher struct {
+	matchers []IdentTokenMatcherInterface
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m AllOfTokenMatcher) IsIdentToken(str string) bool {
+	if len(m.matchers) == 0 {
+		return false
+	}
+
+	for _, matcher := range m.matchers {
+		if !matcher.IsIdentToken(str) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ExtractIdentToken is the implementation for IdentTokenExtractorInterface
+//
+// It delegates the extraction to the first matcher implementing IdentTokenExtractorInterface.
+func (m AllOfTokenMatcher) ExtractIdentToken(str string) string {
+	for _, matcher := range m.matchers {
+		if _, ok := matcher.(IdentTokenExtractorInterface); ok {
+			return extractIdentToken(matcher, str)
+		}
+	}
+
+	return str
+}
+
+// Not returns a matcher considering a string as 'ident' token only if provided matcher doesn't.
+func Not(matcher IdentTokenMatcherInterface) NotTokenMatcher {
+	return NotTokenMatcher{matcher: matcher}
+}
+
+// NotTokenMatcher is an implementation for IdentTokenMatcherInterface negating another matcher (see `Not()`).
+type NotTokenMatcher struct {
+	matcher IdentTokenMatcherInterface
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m NotTokenMatcher) IsIdentToken(str string) bool {
+	return !m.matcher.IsIdentToken(str)
+}
+
+// NewRegexpTokenMatcher returns a matcher considering a string as 'ident' token if it matches
+// at least one of provided regular expressions.
+func NewRegexpTokenMatcher(patterns ...*regexp.Regexp) RegexpTokenMatcher {
+	return RegexpTokenMatcher{patterns: patterns}
+}
+
+// RegexpTokenMatcher is an implementation for IdentTokenMatcherInterface based on regular expressions.
+type RegexpTokenMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m RegexpTokenMatcher) IsIdentToken(str string) bool {
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(str) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewAllowListTokenMatcher returns a matcher considering a string as 'ident' token only if it is exactly
+// one of the provided values.
+func NewAllowListTokenMatcher(values ...string) AllowListTokenMatcher {
+	allowList := make(map[string]bool, len(values))
+	for _, value := range values {
+		allowList[value] = true
+	}
+
+	return AllowListTokenMatcher{allowList: allowList}
+}
+
+// AllowListTokenMatcher is an implementation for IdentTokenMatcherInterface based on an exact allowlist.
+type AllowListTokenMatcher struct {
+	allowList map[string]bool
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m AllowListTokenMatcher) IsIdentToken(str string) bool {
+	return m.allowList[str]
+}
+
+// NewMarkerTokenMatcher returns a matcher considering a string as 'ident' token only if it is explicitly marked
+// as such, either by being wrapped into `${` and `}` (e.g. `${var.foo}`) or by being prefixed with the provided sigil
+// (e.g. `@var.foo` with `@` sigil). Only the wrapping is considered if sigil is empty.
+//
+// Marker is stripped before the token is rendered.
+func NewMarkerTokenMatcher(sigil string) MarkerTokenMatcher {
+	return MarkerTokenMatcher{sigil: sigil}
+}
+
+// MarkerTokenMatcher is an implementation for IdentTokenExtractorInterface based on explicit markers.
+type MarkerTokenMatcher struct {
+	sigil string
+}
+
+// IsIdentToken is the implementation for IdentTokenMatcherInterface.
+func (m MarkerTokenMatcher) IsIdentToken(str string) bool {
+	_, found := m.strip(str)
+
+	return found
+}
+
+// ExtractIdentToken is the implementation for IdentTokenExtractorInterface.
+func (m MarkerTokenMatcher) ExtractIdentToken(str string) string {
+	token, _ := m.strip(str)
+
+	return token
+}
+
+func (m MarkerTokenMatcher) strip(str string) (string, bool) {
+	if m.sigil != "" && len(str) > len(m.sigil) && strings.HasPrefix(str, m.sigil) {
+		return strings.TrimPrefix(str, m.sigil), true
+	}
+
+	// Only a single interpolation wrapping the whole string is a marker, `${a}-${b}` is a template
+	if strings.HasPrefix(str, interpolationStart) && strings.HasSuffix(str, interpolationEnd) &&
+		strings.Count(str, interpolationStart) == 1 && len(str) > len(interpolationStart)+len(interpolationEnd) {
+		return strings.TrimSpace(str[len(interpolationStart) : len(str)-len(interpolationEnd)]), true
+	}
+
+	return str, false
+}
+
+/** Private **/
+
+// extractIdentToken returns the 'ident' token extracted by the matcher if it implements
+// IdentTokenExtractorInterface, else the provided string as is.
+func extractIdentToken(matcher IdentTokenMatcherInterface, str string) string {
+	if extractor, ok := matcher.(IdentTokenExtractorInterface); ok {
+		return extractor.ExtractIdentToken(str)
+	}
+
+	return str
+}
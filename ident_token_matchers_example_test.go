@@ -0,0 +1,37 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func ExampleNewMarkerTokenMatcher() {
+	text := "var.x is cool"
+	sigil := "@var.foo"
+	wrapped := "${local.bar}"
+	allowed := "var.allowed"
+
+	valGen := tfsig.NewValueGeneratorWith(
+		tfsig.AnyOf(tfsig.NewMarkerTokenMatcher("@"), tfsig.NewAllowListTokenMatcher("var.allowed")),
+	)
+	sig := tfsig.NewSignature("my_block")
+	sig.AppendAttribute("attr1", *valGen.ToString(&text))
+	sig.AppendAttribute("attr2", *valGen.ToString(&sigil))
+	sig.AppendAttribute("attr3", *valGen.ToString(&wrapped))
+	sig.AppendAttribute("attr4", *valGen.ToString(&allowed))
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(sig.Build())
+	fmt.Println(string(hclFile.Bytes()))
+
+	// Output:
+	// my_block {
+	//   attr1 = "var.x is cool"
+	//   attr2 = var.foo
+	//   attr3 = local.bar
+	//   attr4 = var.allowed
+	// }
+}
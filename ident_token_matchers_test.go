@@ -0,0 +1,68 @@
+package tfsig_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func TestIdentTokenMatchers(t *testing.T) {
+	t.Parallel()
+
+	marker := tfsig.NewMarkerTokenMatcher("@")
+	prefix := tfsig.NewIdentTokenMatcher()
+	allowList := tfsig.NewAllowListTokenMatcher("var.allowed", "local.allowed")
+	regex := tfsig.NewRegexpTokenMatcher(regexp.MustCompile(`^var\.[a-z_]+$`), regexp.MustCompile(`^each\.`))
+
+	cases := map[string]struct {
+		matcher  tfsig.IdentTokenMatcherInterface
+		value    string
+		expected bool
+		token    string
+	}{
+		"Marker - sigil":               {marker, "@var.foo", true, "var.foo"},
+		"Marker - sigil only":          {marker, "@", false, "@"},
+		"Marker - wrapped":             {marker, "${ var.foo }", true, "var.foo"},
+		"Marker - template":            {marker, "${var.foo}-${var.bar}", false, "${var.foo}-${var.bar}"},
+		"Marker - empty wrapping":      {marker, "${}", false, "${}"},
+		"Marker - not marked":          {marker, "var.foo", false, "var.foo"},
+		"Marker - no sigil":            {tfsig.NewMarkerTokenMatcher(""), "@var.foo", false, "@var.foo"},
+		"AllowList - allowed":          {allowList, "var.allowed", true, "var.allowed"},
+		"AllowList - not allowed":      {allowList, "var.allowed_not", false, "var.allowed_not"},
+		"Regexp - first pattern":       {regex, "var.foo", true, "var.foo"},
+		"Regexp - second pattern":      {regex, "each.value", true, "each.value"},
+		"Regexp - no match":            {regex, "var.foo is cool", false, "var.foo is cool"},
+		"AnyOf - marker":               {tfsig.AnyOf(allowList, marker), "@var.foo", true, "var.foo"},
+		"AnyOf - allowList":            {tfsig.AnyOf(allowList, marker), "var.allowed", true, "var.allowed"},
+		"AnyOf - none":                 {tfsig.AnyOf(allowList, marker), "var.foo", false, "var.foo"},
+		"AllOf - all":                  {tfsig.AllOf(prefix, regex), "var.foo", true, "var.foo"},
+		"AllOf - only one":             {tfsig.AllOf(prefix, regex), "var.foo is cool", false, "var.foo is cool"},
+		"AllOf - with extractor":       {tfsig.AllOf(tfsig.Not(allowList), marker), "@var.foo", true, "var.foo"},
+		"AllOf - empty":                {tfsig.AllOf(), "var.foo", false, "var.foo"},
+		"Not - prefix and not allowed": {tfsig.AllOf(prefix, tfsig.Not(allowList)), "var.allowed", false, "var.allowed"},
+		"Not":                          {tfsig.Not(prefix), "foo", true, "foo"},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := tcase.matcher.IsIdentToken(tcase.value); actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expected, actual)
+				}
+
+				extractor, ok := tcase.matcher.(tfsig.IdentTokenExtractorInterface)
+				if !ok {
+					return
+				}
+
+				if actual := extractor.ExtractIdentToken(tcase.value); actual != tcase.token {
+					t.Errorf("Case \"%s\": expected token %q, got %q", t.Name(), tcase.token, actual)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,640 @@
+/*
+Package importer provides a way to generate configuration from a state, as returned by `terraform show -json`
+command, in order to adopt existing infrastructure
+
+Each managed resource of the root module is converted into a resource BlockSignature, alongside a matching `import`
+block for each resource instance. Instances created with `count` or `for_each` are grouped back into a single resource.
+*/
+package importer
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/schema"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// ErrMixedInstanceKeys is returned by `Import()` when instances of a resource use both `count` and `for_each` keys.
+var ErrMixedInstanceKeys = errors.New("mixed instance keys")
+
+// Option is a functional option used to configure `Import()`.
+type Option func(c *config)
+
+// WithSchema provides the provider schemas used to drop computed-only attributes and to render nested blocks as
+// blocks (they are rendered as attributes otherwise).
+func WithSchema(providersSchema *schema.ProvidersSchema) Option {
+	return func(c *config) {
+		c.schema = providersSchema
+	}
+}
+
+// Result holds signatures generated by `Import()`.
+type Result struct {
+	// Variables contains a sensitive `variable` block for each sensitive value which differs between grouped instances
+	Variables []*tfsig.BlockSignature
+	// Imports contains an `import` block for each resource instance having an `id` attribute
+	Imports []*tfsig.BlockSignature
+	// Resources contains a `resource` block for each resource, in state order
+	Resources []*tfsig.BlockSignature
+}
+
+// File returns a FileSignature containing variable blocks, followed by import blocks and resource blocks.
+func (r *Result) File() *tfsig.FileSignature {
+	sigs := append(append([]*tfsig.BlockSignature{}, r.Variables...), r.Imports...)
+
+	return tfsig.NewFileSignature(append(sigs, r.Resources...)...)
+}
+
+// Import converts managed resources of the root module of the provided state into signatures
+// (data sources and child modules are ignored)
+//
+// Null values are not rendered, top-level attributes marked as sensitive in the state are marked as Sensitive.
+// Instances created with `count` or `for_each` are grouped into a single resource: values shared by all instances
+// are rendered as is, other values are rendered as `count.index` or `each.value` lookups (differing nested blocks
+// are rendered as `dynamic` blocks).
+// `count` instances are sorted by index. As `count` can't express missing indices, instances whose indices are not
+// contiguous from 0 are grouped with `for_each` instead, keyed by their index.
+// Sensitive values which differ between grouped instances are never inlined: they are rendered as lookups into a
+// sensitive variable named `<type>_<name>_<attribute>` which must be provided by the user (see `Result.Variables`).
+func Import(state *State, opts ...Option) (*Result, error) {
+	conf := config{schema: nil}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	result := &Result{
+		Variables: []*tfsig.BlockSignature{},
+		Imports:   []*tfsig.BlockSignature{},
+		Resources: []*tfsig.BlockSignature{},
+	}
+	if state == nil || state.Values == nil || state.Values.RootModule == nil {
+		return result, nil
+	}
+
+	for _, group := range groupInstances(state.Values.RootModule.Resources) {
+		instances, err := newInstances(group)
+		if err != nil {
+			return nil, err
+		}
+
+		instances = sortCountInstances(instances)
+
+		sig, variables, err := conf.newResource(instances)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Variables = append(result.Variables, variables...)
+		result.Resources = append(result.Resources, sig)
+
+		for _, inst := range instances {
+			if importSig := newImportBlock(inst.resource, inst.value); importSig != nil {
+				result.Imports = append(result.Imports, importSig)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+/** Private **/
+
+type config struct {
+	schema *schema.ProvidersSchema
+}
+
+type instance struct {
+	resource *Resource
+	value    cty.Value
+}
+
+// groupInstances returns instances of managed resources grouped by resource address, in state order.
+func groupInstances(resources []*Resource) [][]*Resource {
+	groups := [][]*Resource{}
+	positions := map[string]int{}
+
+	for _, res := range resources {
+		if res == nil || !res.IsManaged() {
+			continue
+		}
+
+		address := res.resourceAddress()
+		if pos, exists := positions[address]; exists {
+			groups[pos] = append(groups[pos], res)
+
+			continue
+		}
+
+		positions[address] = len(groups)
+		groups = append(groups, []*Resource{res})
+	}
+
+	return groups
+}
+
+func newInstances(group []*Resource) ([]instance, error) {
+	instances := make([]instance, len(group))
+
+	for idx, res := range group {
+		val, err := res.GetValue()
+		if err != nil {
+			return nil, err
+		}
+
+		instances[idx] = instance{resource: res, value: markSensitive(val, res.GetSensitiveAttributes())}
+	}
+
+	return instances, nil
+}
+
+// sortCountInstances sorts `count` instances by index, and converts them to `for_each` instances keyed by their
+// index if indices are not contiguous from 0. Other instances are returned as is.
+func sortCountInstances(instances []instance) []instance {
+	for _, inst := range instances {
+		if _, isCount := inst.resource.Index.(float64); !isCount {
+			return instances
+		}
+	}
+
+	sorted := slices.Clone(instances)
+	slices.SortStableFunc(sorted, func(a, b instance) int {
+		return cmp.Compare(countIndex(a), countIndex(b))
+	})
+
+	for idx, inst := range sorted {
+		if countIndex(inst) != float64(idx) {
+			return toForEachInstances(sorted)
+		}
+	}
+
+	return sorted
+}
+
+func countIndex(inst instance) float64 {
+	index, _ := inst.resource.Index.(float64)
+
+	return index
+}
+
+// toForEachInstances returns a copy of provided `count` instances, keyed by their index as string.
+func toForEachInstances(instances []instance) []instance {
+	converted := make([]instance, len(instances))
+
+	for idx, inst := range instances {
+		res := *inst.resource
+		res.Index = strconv.FormatFloat(countIndex(inst), 'f', -1, 64)
+		converted[idx] = instance{resource: &res, value: inst.value}
+	}
+
+	return converted
+}
+
+// newResource returns the resource signature, alongside variables required by the resource if any.
+func (c config) newResource(instances []instance) (*tfsig.BlockSignature, []*tfsig.BlockSignature, error) {
+	first := instances[0].resource
+	sig := tfsig.NewResource(first.Type, first.Name)
+	block := c.resourceBlock(first.Type)
+
+	if first.Index == nil {
+		appendBody(sig, instances[0].value, block)
+
+		return sig, nil, nil
+	}
+
+	return newGroupedResource(sig, instances, block)
+}
+
+func (c config) resourceBlock(resourceType string) *schema.Block {
+	if c.schema == nil {
+		return nil
+	}
+
+	resSchema, exists := c.schema.ResourceSchema(resourceType)
+	if !exists {
+		return nil
+	}
+
+	return resSchema.Block
+}
+
+// newGroupedResource appends `count` or `for_each` meta-argument to the resource, followed by values
+//
+// It also returns a variable for each sensitive differing value.
+func newGroupedResource(
+	sig *tfsig.BlockSignature,
+	instances []instance,
+	block *schema.Block,
+) (*tfsig.BlockSignature, []*tfsig.BlockSignature, error) {
+	isCount, err := isCountGroup(instances)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared, allDiffering := splitValues(instances, block)
+	differing := []string{}
+	variables := map[string]*tfsig.BlockSignature{}
+
+	for _, name := range allDiffering {
+		if isSensitive(instances, name) {
+			variables[name] = newSensitiveVariable(instances[0].resource, name)
+		} else {
+			differing = append(differing, name)
+		}
+	}
+
+	appendMetaArgument(sig, instances, isCount, differing)
+
+	lookup := newValueLookup(instances, variables, isCount)
+	sharedBlocks := appendAttributes(sig, shared, block)
+	differingBlocks := []string{}
+
+	for _, name := range allDiffering {
+		if nestedBlock(block, name) != nil {
+			differingBlocks = append(differingBlocks, name)
+		} else {
+			sig.AppendAttribute(name, lookup(name))
+		}
+	}
+
+	for _, name := range sharedBlocks {
+		appendNestedBlocks(sig, name, shared.GetAttr(name), nestedBlock(block, name))
+	}
+
+	for _, name := range differingBlocks {
+		sig.AppendChild(newDynamicBlock(name, lookup(name), nestedBlock(block, name)))
+	}
+
+	variableSigs := make([]*tfsig.BlockSignature, 0, len(variables))
+
+	for _, name := range allDiffering {
+		if variable, exists := variables[name]; exists {
+			variableSigs = append(variableSigs, variable)
+		}
+	}
+
+	return sig, variableSigs, nil
+}
+
+// isCountGroup returns true if instances are indexed by `count`, false if they are indexed by `for_each`.
+func isCountGroup(instances []instance) (bool, error) {
+	_, isCount := instances[0].resource.Index.(float64)
+
+	for _, inst := range instances {
+		if _, ok := inst.resource.Index.(float64); ok != isCount {
+			return false, fmt.Errorf("%w for %s", ErrMixedInstanceKeys, inst.resource.resourceAddress())
+		}
+	}
+
+	return isCount, nil
+}
+
+// appendMetaArgument appends the `count` meta-argument, or the `for_each` one holding differing values of each
+// instance, followed by an empty line.
+func appendMetaArgument(sig *tfsig.BlockSignature, instances []instance, isCount bool, differing []string) {
+	if isCount {
+		sig.AppendAttribute("count", cty.NumberIntVal(int64(len(instances))))
+	} else {
+		entries := make([]tokens.ObjectEntry, len(instances))
+		for idx, inst := range instances {
+			entries[idx] = tokens.NewObjectEntry(fmt.Sprint(inst.resource.Index), differingObject(inst, differing))
+		}
+
+		sig.AppendAttribute("for_each", tokens.NewOrderedObjectValue(entries...))
+	}
+
+	sig.AppendEmptyLine()
+}
+
+// newValueLookup returns a function returning the expression used to look up the differing value of the current
+// instance.
+func newValueLookup(
+	instances []instance,
+	variables map[string]*tfsig.BlockSignature,
+	isCount bool,
+) func(name string) cty.Value {
+	return func(name string) cty.Value {
+		if variable, exists := variables[name]; exists {
+			key := "each.key"
+			if isCount {
+				key = "count.index"
+			}
+
+			return *tokens.NewExpressionValue("var." + variable.GetLabels()[0] + "[" + key + "]")
+		}
+
+		if !isCount {
+			return *tokens.NewIdentValue("each.value." + name)
+		}
+
+		values := make([]cty.Value, len(instances))
+		for idx, inst := range instances {
+			values[idx] = differingObject(inst, []string{name}).GetAttr(name)
+		}
+
+		return newCountLookup(cty.TupleVal(values))
+	}
+}
+
+// splitValues returns values shared by all instances and names of values which differ between instances.
+func splitValues(instances []instance, block *schema.Block) (cty.Value, []string) {
+	shared := map[string]cty.Value{}
+	differing := []string{}
+
+	for _, name := range attributeNames(instances) {
+		if isComputedOnly(block, name) {
+			continue
+		}
+
+		first := attributeValue(instances[0].value, name)
+
+		switch {
+		case isSharedValue(instances, name, first):
+			shared[name] = first
+		case allNull(instances, name):
+		default:
+			differing = append(differing, name)
+		}
+	}
+
+	return cty.ObjectVal(shared), differing
+}
+
+// attributeNames returns sorted names of attributes of all instances.
+func attributeNames(instances []instance) []string {
+	names := []string{}
+	known := map[string]bool{}
+
+	for _, inst := range instances {
+		for name := range inst.value.Type().AttributeTypes() {
+			if !known[name] {
+				known[name] = true
+
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// isSharedValue returns true if all instances have the provided value for the attribute.
+func isSharedValue(instances []instance, name string, value cty.Value) bool {
+	for _, inst := range instances {
+		if !attributeValue(inst.value, name).RawEquals(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newSensitiveVariable returns a sensitive `variable` block holding values of the provided attribute for each instance
+// of the resource.
+func newSensitiveVariable(res *Resource, name string) *tfsig.BlockSignature {
+	sig := tfsig.NewSignature("variable", res.Type+"_"+res.Name+"_"+name)
+	sig.AppendAttribute("description", cty.StringVal("Values of "+res.resourceAddress()+"."+name+" for each instance"))
+	sig.AppendAttribute("sensitive", cty.True)
+
+	return sig
+}
+
+// isSensitive returns true if the value of one of the instances is marked as Sensitive.
+func isSensitive(instances []instance, name string) bool {
+	for _, inst := range instances {
+		if attributeValue(inst.value, name).ContainsMarked() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// differingObject returns an object containing provided values of the instance.
+func differingObject(inst instance, names []string) cty.Value {
+	values := map[string]cty.Value{}
+	for _, name := range names {
+		values[name] = attributeValue(inst.value, name)
+	}
+
+	return cty.ObjectVal(values)
+}
+
+// appendBody appends attributes of the provided object value, followed by nested blocks if a schema is provided.
+func appendBody(sig *tfsig.BlockSignature, value cty.Value, block *schema.Block) {
+	for _, name := range appendAttributes(sig, value, block) {
+		appendNestedBlocks(sig, name, value.GetAttr(name), nestedBlock(block, name))
+	}
+}
+
+// appendAttributes appends attributes of the provided object value and returns names of non-null nested blocks.
+func appendAttributes(sig *tfsig.BlockSignature, value cty.Value, block *schema.Block) []string {
+	nestedNames := []string{}
+
+	for it := value.ElementIterator(); it.Next(); {
+		key, val := it.Element()
+		name := key.AsString()
+
+		switch {
+		case isComputedOnly(block, name), val.IsNull():
+		case nestedBlock(block, name) != nil:
+			nestedNames = append(nestedNames, name)
+		default:
+			sig.AppendAttribute(name, val)
+		}
+	}
+
+	return nestedNames
+}
+
+func appendNestedBlocks(sig *tfsig.BlockSignature, name string, value cty.Value, nested *schema.NestedBlock) {
+	unmarked, _ := value.Unmark()
+	if unmarked.IsNull() || !unmarked.IsKnown() {
+		return
+	}
+
+	valType := unmarked.Type()
+	if valType.IsObjectType() && nested.NestingMode != schema.NestingMap {
+		child := tfsig.NewSignature(name)
+		appendBody(child, unmarked, nested.Block)
+		sig.AppendChild(child)
+
+		return
+	}
+
+	for it := unmarked.ElementIterator(); it.Next(); {
+		key, elem := it.Element()
+		if elem.IsNull() {
+			continue
+		}
+
+		child := tfsig.NewSignature(name)
+		if nested.NestingMode == schema.NestingMap {
+			child = tfsig.NewSignature(name, key.AsString())
+		}
+
+		appendBody(child, elem, nested.Block)
+		sig.AppendChild(child)
+	}
+}
+
+// newDynamicBlock returns a `dynamic` block iterating over the provided value, nested values are referenced through
+// the iterator (e.g. `ebs_block_device.value.device_name`).
+func newDynamicBlock(name string, forEach cty.Value, nested *schema.NestedBlock) *tfsig.BlockSignature {
+	sig := tfsig.NewSignature("dynamic", name)
+
+	if nested.NestingMode == schema.NestingSingle || nested.NestingMode == schema.NestingGroup {
+		forEach = newSingleBlockForEach(forEach)
+	}
+
+	sig.AppendAttribute("for_each", forEach)
+
+	if nested.NestingMode == schema.NestingMap {
+		sig.AppendAttribute("labels", *tokens.NewIdentListValue([]string{name + ".key"}))
+	}
+
+	content := tfsig.NewSignature("content")
+
+	for _, attrName := range slices.Sorted(maps.Keys(nested.Block.Attributes)) {
+		if !isComputedOnly(nested.Block, attrName) {
+			content.AppendAttribute(attrName, *tokens.NewIdentValue(name + ".value." + attrName))
+		}
+	}
+
+	for _, blockName := range slices.Sorted(maps.Keys(nested.Block.BlockTypes)) {
+		childForEach := *tokens.NewIdentValue(name + ".value." + blockName)
+		content.AppendChild(newDynamicBlock(blockName, childForEach, nested.Block.BlockTypes[blockName]))
+	}
+
+	sig.AppendChild(content)
+
+	return sig
+}
+
+// newSingleBlockForEach wraps a single nested block value into a list, as `dynamic` blocks iterate over collections.
+func newSingleBlockForEach(value cty.Value) cty.Value {
+	return newExpressionAround("value == null ? [] : [value]", value)
+}
+
+// newCountLookup returns the `<values>[count.index]` expression.
+func newCountLookup(values cty.Value) cty.Value {
+	return newExpressionAround("value[count.index]", values)
+}
+
+// newExpressionAround returns the provided expression where each `value` ident is replaced by the provided value.
+func newExpressionAround(expr string, value cty.Value) cty.Value {
+	exprTokens, err := tokens.FromExpression(expr)
+	if err != nil {
+		panic(fmt.Sprintf("invalid expression %q: %s", expr, err))
+	}
+
+	valueTokens := tokens.Generate(&value)
+	newTokens := hclwrite.Tokens{}
+
+	for _, token := range exprTokens {
+		if token.Type == hclsyntax.TokenIdent && string(token.Bytes) == "value" {
+			// Tokens are copied as formatting updates them in place
+			for _, valueToken := range valueTokens {
+				tokenCopy := *valueToken
+				newTokens = append(newTokens, &tokenCopy)
+			}
+
+			continue
+		}
+
+		newTokens = append(newTokens, token)
+	}
+
+	return tokens.ToValue(newTokens)
+}
+
+// newImportBlock returns an `import` block for the resource instance, nil if the instance has no `id` attribute.
+func newImportBlock(res *Resource, value cty.Value) *tfsig.BlockSignature {
+	idValue := attributeValue(value, "id")
+	if idValue.IsNull() || !idValue.IsKnown() || idValue.Type() != cty.String {
+		return nil
+	}
+
+	address := res.resourceAddress()
+
+	switch key := res.Index.(type) {
+	case float64:
+		address += "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+	case string:
+		address += "[" + strconv.Quote(key) + "]"
+	}
+
+	sig := tfsig.NewSignature("import")
+	sig.AppendAttribute("to", *tokens.NewExpressionValue(address))
+	sig.AppendAttribute("id", idValue)
+
+	return sig
+}
+
+func markSensitive(value cty.Value, sensitive map[string]bool) cty.Value {
+	if len(sensitive) == 0 {
+		return value
+	}
+
+	values := value.AsValueMap()
+	for name := range sensitive {
+		if val, exists := values[name]; exists && !val.IsNull() {
+			values[name] = val.Mark(tfsig.Sensitive)
+		}
+	}
+
+	return cty.ObjectVal(values)
+}
+
+func attributeValue(value cty.Value, name string) cty.Value {
+	if !value.Type().HasAttribute(name) {
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+
+	return value.GetAttr(name)
+}
+
+func allNull(instances []instance, name string) bool {
+	for _, inst := range instances {
+		if !attributeValue(inst.value, name).IsNull() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func nestedBlock(block *schema.Block, name string) *schema.NestedBlock {
+	if block == nil {
+		return nil
+	}
+
+	nested, exists := block.BlockTypes[name]
+	if !exists || nested.Block == nil {
+		return nil
+	}
+
+	return nested
+}
+
+func isComputedOnly(block *schema.Block, name string) bool {
+	if block == nil {
+		return false
+	}
+
+	attr, exists := block.Attributes[name]
+
+	return exists && attr.Computed && !attr.Optional && !attr.Required
+}
@@ -0,0 +1,324 @@
+package importer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yoanm/go-tfsig/importer"
+	"github.com/yoanm/go-tfsig/schema"
+	"github.com/yoanm/go-tfsig/testutils"
+)
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	state, err := importer.LoadState("testdata/state.json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	providersSchema, err := schema.Load("testdata/providers.schema.json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	cases := map[string]struct {
+		opts       []importer.Option
+		goldenFile string
+	}{
+		"Without schema": {
+			opts:       nil,
+			goldenFile: "import.default",
+		},
+		"With schema": {
+			opts:       []importer.Option{importer.WithSchema(providersSchema)},
+			goldenFile: "import.schema",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				result, importErr := importer.Import(state, tcase.opts...)
+				if importErr != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", tcname, importErr)
+				}
+
+				if len(result.Imports) != 5 || len(result.Resources) != 3 {
+					t.Errorf(
+						"Case \"%s\": expected 5 imports and 3 resources, got %d and %d",
+						tcname,
+						len(result.Imports),
+						len(result.Resources),
+					)
+				}
+
+				importErr = testutils.EnsureFileEqualsGoldenFile(result.File().BuildRedacted(), tcase.goldenFile)
+				if importErr != nil {
+					t.Errorf("Case \"%s\": %v", tcname, importErr)
+				}
+			},
+		)
+	}
+}
+
+func TestImport_sensitive(t *testing.T) {
+	t.Parallel()
+
+	state, err := importer.LoadState("testdata/state.json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	result, err := importer.Import(state)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, elem := range result.Resources[0].GetElements() {
+		if elem.IsBodyAttribute() && elem.GetBodyAttribute().IsMarked() != (elem.GetName() == "api_key") {
+			t.Errorf("Case \"%s\": unexpected sensitive mark", elem.GetName())
+		}
+	}
+}
+
+func TestImport_groupedSensitive(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		content  string
+		expected string
+	}{
+		"Count": {
+			content: `{"values": {"root_module": {"resources": [
+				{"address": "a.b[0]", "mode": "managed", "type": "a", "name": "b", "index": 0,
+					"values": {"name": "b0", "password": "secret0", "token": "shared"},
+					"sensitive_values": {"password": true, "token": true}},
+				{"address": "a.b[1]", "mode": "managed", "type": "a", "name": "b", "index": 1,
+					"values": {"name": "b1", "password": "secret1", "token": "shared"},
+					"sensitive_values": {"password": true, "token": true}}
+			]}}}`,
+			expected: `variable "a_b_password" {
+  description = "Values of a.b.password for each instance"
+  sensitive   = true
+}
+
+resource "a" "b" {
+  count = 2
+
+  token    = "(sensitive)"
+  name     = ["b0", "b1"][count.index]
+  password = var.a_b_password[count.index]
+}
+`,
+		},
+		"For each": {
+			content: `{"values": {"root_module": {"resources": [
+				{"address": "a.b[\"x\"]", "mode": "managed", "type": "a", "name": "b", "index": "x",
+					"values": {"name": "x", "password": "secret_x"}, "sensitive_values": {"password": true}},
+				{"address": "a.b[\"y\"]", "mode": "managed", "type": "a", "name": "b", "index": "y",
+					"values": {"name": "y", "password": "secret_y"}}
+			]}}}`,
+			expected: `variable "a_b_password" {
+  description = "Values of a.b.password for each instance"
+  sensitive   = true
+}
+
+resource "a" "b" {
+  for_each = {
+    x = {
+      name = "x"
+    }
+    y = {
+      name = "y"
+    }
+  }
+
+  name     = each.value.name
+  password = var.a_b_password[each.key]
+}
+`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				state, err := importer.ParseState([]byte(tcase.content))
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", tcname, err)
+				}
+
+				result, err := importer.Import(state)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", tcname, err)
+				}
+
+				if err = testutils.EnsureFileContentEquals(result.File().BuildRedacted(), tcase.expected); err != nil {
+					t.Errorf("Case \"%s\": %v", tcname, err)
+				}
+			},
+		)
+	}
+}
+
+func TestImport_countIndices(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		content  string
+		expected string
+	}{
+		"Out of order": {
+			content: `{"values": {"root_module": {"resources": [
+				{"address": "a.b[1]", "mode": "managed", "type": "a", "name": "b", "index": 1,
+					"values": {"id": "id1", "ami": "b"}},
+				{"address": "a.b[0]", "mode": "managed", "type": "a", "name": "b", "index": 0,
+					"values": {"id": "id0", "ami": "a"}}
+			]}}}`,
+			expected: `import {
+  to = a.b[0]
+  id = "id0"
+}
+
+import {
+  to = a.b[1]
+  id = "id1"
+}
+
+resource "a" "b" {
+  count = 2
+
+  ami = ["a", "b"][count.index]
+  id  = ["id0", "id1"][count.index]
+}
+`,
+		},
+		"Gap": {
+			content: `{"values": {"root_module": {"resources": [
+				{"address": "a.b[2]", "mode": "managed", "type": "a", "name": "b", "index": 2,
+					"values": {"id": "id2", "ami": "b"}},
+				{"address": "a.b[0]", "mode": "managed", "type": "a", "name": "b", "index": 0,
+					"values": {"id": "id0", "ami": "a"}}
+			]}}}`,
+			expected: `import {
+  to = a.b["0"]
+  id = "id0"
+}
+
+import {
+  to = a.b["2"]
+  id = "id2"
+}
+
+resource "a" "b" {
+  for_each = {
+    "0" = {
+      ami = "a"
+      id  = "id0"
+    }
+    "2" = {
+      ami = "b"
+      id  = "id2"
+    }
+  }
+
+  ami = each.value.ami
+  id  = each.value.id
+}
+`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				state, err := importer.ParseState([]byte(tcase.content))
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", tcname, err)
+				}
+
+				result, err := importer.Import(state)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", tcname, err)
+				}
+
+				if err = testutils.EnsureFileContentEquals(result.File().Build(), tcase.expected); err != nil {
+					t.Errorf("Case \"%s\": %v", tcname, err)
+				}
+			},
+		)
+	}
+}
+
+func TestImport_errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		content  string
+		expected error
+	}{
+		"Mixed instance keys": {
+			content: `{"values": {"root_module": {"resources": [
+				{"address": "a.b[0]", "mode": "managed", "type": "a", "name": "b", "index": 0, "values": {}},
+				{"address": "a.b[\"k\"]", "mode": "managed", "type": "a", "name": "b", "index": "k", "values": {}}
+			]}}}`,
+			expected: importer.ErrMixedInstanceKeys,
+		},
+		"Invalid values": {
+			content: `{"values": {"root_module": {"resources": [
+				{"address": "a.b", "mode": "managed", "type": "a", "name": "b", "values": ["not", "an", "object"]}
+			]}}}`,
+			expected: importer.ErrInvalidState,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				state, err := importer.ParseState([]byte(tcase.content))
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", tcname, err)
+				}
+
+				if _, err = importer.Import(state); !errors.Is(err, tcase.expected) {
+					t.Errorf("Case \"%s\": expected %v, got %v", tcname, tcase.expected, err)
+				}
+			},
+		)
+	}
+}
+
+func TestParseState(t *testing.T) {
+	t.Parallel()
+
+	if _, err := importer.ParseState([]byte(`{"values": []}`)); !errors.Is(err, importer.ErrInvalidState) {
+		t.Errorf("expected %v, got %v", importer.ErrInvalidState, err)
+	}
+
+	if _, err := importer.LoadState("testdata/unknown.json"); err == nil {
+		t.Errorf("expected an error for an unknown file")
+	}
+
+	state, err := importer.ParseState([]byte(`{"format_version": "1.0"}`))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	result, err := importer.Import(state)
+	if err != nil || len(result.Imports) != 0 || len(result.Resources) != 0 {
+		t.Errorf("expected an empty result, got %v (%v)", result, err)
+	}
+}
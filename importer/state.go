@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ErrInvalidState is returned by `LoadState()` and `ParseState()` when the document can't be decoded.
+var ErrInvalidState = errors.New("invalid state document")
+
+const managedMode = "managed"
+
+// State is the document returned by `terraform show -json` command (only fields used by the importer are decoded).
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type State struct {
+	FormatVersion    string       `json:"format_version"`
+	TerraformVersion string       `json:"terraform_version"`
+	Values           *StateValues `json:"values"`
+}
+
+// StateValues holds the root module of the state.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type StateValues struct {
+	RootModule *Module `json:"root_module"`
+}
+
+// Module holds resource instances of a module and its child modules.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type Module struct {
+	Address      string      `json:"address"`
+	Resources    []*Resource `json:"resources"`
+	ChildModules []*Module   `json:"child_modules"`
+}
+
+// Resource is a resource instance of the state.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type Resource struct {
+	Address         string          `json:"address"`
+	Mode            string          `json:"mode"`
+	Type            string          `json:"type"`
+	Name            string          `json:"name"`
+	Index           any             `json:"index"`
+	ProviderName    string          `json:"provider_name"`
+	SchemaVersion   int64           `json:"schema_version"`
+	Values          json.RawMessage `json:"values"`
+	SensitiveValues json.RawMessage `json:"sensitive_values"`
+}
+
+// LoadState reads and decodes the state document located at the provided path (see `ParseState()`).
+func LoadState(path string) (*State, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return ParseState(content)
+}
+
+// ParseState decodes the provided `terraform show -json` output.
+func ParseState(content []byte) (*State, error) {
+	state := &State{FormatVersion: "", TerraformVersion: "", Values: nil}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+
+	return state, nil
+}
+
+// IsManaged returns true for managed resources (i.e. not data sources).
+func (r *Resource) IsManaged() bool {
+	return r.Mode == managedMode
+}
+
+// GetValue returns values of the resource instance as an object, `cty.EmptyObjectVal` if there is no value.
+func (r *Resource) GetValue() (cty.Value, error) {
+	if len(r.Values) == 0 {
+		return cty.EmptyObjectVal, nil
+	}
+
+	valType, err := ctyjson.ImpliedType(r.Values)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("%s: %w: %w", r.Address, ErrInvalidState, err)
+	}
+
+	val, err := ctyjson.Unmarshal(r.Values, valType)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("%s: %w: %w", r.Address, ErrInvalidState, err)
+	}
+
+	if !val.Type().IsObjectType() {
+		return cty.NilVal, fmt.Errorf("%s: %w: values must be an object", r.Address, ErrInvalidState)
+	}
+
+	return val, nil
+}
+
+// GetSensitiveAttributes returns names of top-level attributes marked as sensitive.
+func (r *Resource) GetSensitiveAttributes() map[string]bool {
+	sensitive := map[string]bool{}
+	values := map[string]any{}
+
+	if err := json.Unmarshal(r.SensitiveValues, &values); err != nil {
+		return sensitive
+	}
+
+	for name, value := range values {
+		if isSensitive, ok := value.(bool); ok && isSensitive {
+			sensitive[name] = true
+		}
+	}
+
+	return sensitive
+}
+
+/** Private **/
+
+// resourceAddress returns the address of the resource, without instance key.
+func (r *Resource) resourceAddress() string {
+	return r.Type + "." + r.Name
+}
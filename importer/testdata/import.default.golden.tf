@@ -0,0 +1,83 @@
+import {
+  to = aws_s3_bucket.logs
+  id = "logs-bucket"
+}
+
+import {
+  to = aws_instance.web[0]
+  id = "i-0"
+}
+
+import {
+  to = aws_instance.web[1]
+  id = "i-1"
+}
+
+import {
+  to = aws_instance.app["blue"]
+  id = "i-blue"
+}
+
+import {
+  to = aws_instance.app["green"]
+  id = "i-green"
+}
+
+resource "aws_s3_bucket" "logs" {
+  api_key = "(sensitive)"
+  arn     = "arn:aws:s3:::logs-bucket"
+  bucket  = "logs-bucket"
+  id      = "logs-bucket"
+  tags = {
+    Team = "platform"
+  }
+  versioning = [{
+    enabled = true
+  }]
+}
+
+resource "aws_instance" "web" {
+  count = 2
+
+  ami = "ami-123"
+  metadata_options = {
+    http_tokens = "required"
+  }
+  id            = ["i-0", "i-1"][count.index]
+  instance_type = ["t3.micro", "t3.large"][count.index]
+  root_block_device = [[{
+    volume_id   = "vol-0"
+    volume_size = 8
+    }], [{
+    volume_id   = "vol-1"
+    volume_size = 20
+  }]][count.index]
+}
+
+resource "aws_instance" "app" {
+  for_each = {
+    blue = {
+      id               = "i-blue"
+      metadata_options = null
+      tags = {
+        Color = "blue"
+      }
+    }
+    green = {
+      id = "i-green"
+      metadata_options = {
+        http_tokens = "optional"
+      }
+      tags = {
+        Color = "green"
+      }
+    }
+  }
+
+  ami               = "ami-123"
+  instance_type     = "t3.micro"
+  root_block_device = []
+  id                = each.value.id
+  metadata_options  = each.value.metadata_options
+  tags              = each.value.tags
+}
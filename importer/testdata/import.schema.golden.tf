@@ -0,0 +1,86 @@
+import {
+  to = aws_s3_bucket.logs
+  id = "logs-bucket"
+}
+
+import {
+  to = aws_instance.web[0]
+  id = "i-0"
+}
+
+import {
+  to = aws_instance.web[1]
+  id = "i-1"
+}
+
+import {
+  to = aws_instance.app["blue"]
+  id = "i-blue"
+}
+
+import {
+  to = aws_instance.app["green"]
+  id = "i-green"
+}
+
+resource "aws_s3_bucket" "logs" {
+  api_key = "(sensitive)"
+  bucket  = "logs-bucket"
+  tags = {
+    Team = "platform"
+  }
+  versioning {
+    enabled = true
+  }
+}
+
+resource "aws_instance" "web" {
+  count = 2
+
+  ami           = "ami-123"
+  instance_type = ["t3.micro", "t3.large"][count.index]
+  metadata_options {
+    http_tokens = "required"
+  }
+  dynamic "root_block_device" {
+    for_each = [[{
+      volume_id   = "vol-0"
+      volume_size = 8
+      }], [{
+      volume_id   = "vol-1"
+      volume_size = 20
+    }]][count.index]
+    content {
+      volume_size = root_block_device.value.volume_size
+    }
+  }
+}
+
+resource "aws_instance" "app" {
+  for_each = {
+    blue = {
+      metadata_options = null
+      tags = {
+        Color = "blue"
+      }
+    }
+    green = {
+      metadata_options = {
+        http_tokens = "optional"
+      }
+      tags = {
+        Color = "green"
+      }
+    }
+  }
+
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+  tags          = each.value.tags
+  dynamic "metadata_options" {
+    for_each = each.value.metadata_options == null ? [] : [each.value.metadata_options]
+    content {
+      http_tokens = metadata_options.value.http_tokens
+    }
+  }
+}
@@ -0,0 +1,341 @@
+package tfsig
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// ErrMergeConflict is returned by `Merge()` when an attribute is defined in both signatures with a different value
+// and `ConflictError` strategy is used.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// ErrMergeTypeMismatch is returned by `Merge()` when both signatures don't have the same block type.
+var ErrMergeTypeMismatch = errors.New("unable to merge blocks with different types")
+
+// ConflictStrategy defines how `Merge()` behaves when an attribute is defined in both signatures.
+type ConflictStrategy int
+
+const (
+	// OverlayWins keeps the value from the overlay signature (default).
+	OverlayWins ConflictStrategy = iota
+	// BaseWins keeps the value from the base signature.
+	BaseWins
+	// ConflictError makes `Merge()` return an `ErrMergeConflict` error.
+	ConflictError
+)
+
+// MergeOptions is used as argument for `Merge()` function.
+type MergeOptions struct {
+	// Strategy to use when an attribute is defined in both signatures with a different value
+	Strategy ConflictStrategy
+	// BlockKey returns the key used to match nested blocks between both signatures.
+	// Nested blocks are matched by type and labels if nil.
+	BlockKey func(block *BlockSignature) string
+}
+
+// Merge returns a new signature resulting of the merge of overlay signature into the base signature
+//
+// Attributes defined in both signatures are merged based on the provided strategy, except for object, map and ordered
+// object (see `tokens.OrderedObject`) values which are deep-merged. Result is an ordered object if one of the values
+// is an ordered object: base keys order is kept and overlay keys which don't exist in base value are appended.
+// Nested blocks are matched by type and labels (or by `MergeOptions.BlockKey` if provided) and merged recursively.
+// Block format options are taken from the overlay signature, or from the base signature if overlay doesn't define any.
+// Base elements order is preserved, overlay attributes which don't exist in base signature are inserted after the last
+// base attribute and overlay blocks which don't exist in base signature are appended.
+//
+// Provided signatures are not modified and the returned signature doesn't share any block with them.
+func Merge(base, overlay *BlockSignature, opts MergeOptions) (*BlockSignature, error) {
+	if base == nil || overlay == nil {
+		if base == nil {
+			return cloneBlock(overlay), nil
+		}
+
+		return cloneBlock(base), nil
+	}
+
+	if base.GetType() != overlay.GetType() {
+		return nil, fmt.Errorf("%w: %q and %q", ErrMergeTypeMismatch, base.GetType(), overlay.GetType())
+	}
+
+	return mergeBlocks([]string{base.GetHeader()}, base, overlay, opts)
+}
+
+/** Private **/
+
+func mergeBlocks(path []string, base, overlay *BlockSignature, opts MergeOptions) (*BlockSignature, error) {
+	keyFn := opts.BlockKey
+	if keyFn == nil {
+		keyFn = (*BlockSignature).GetHeader
+	}
+
+	pairs, _, overlayLeft := matchBlocks(childBlocks(base.GetElements()), childBlocks(overlay.GetElements()), keyFn)
+
+	overlayByBase := map[*BlockSignature]*BlockSignature{}
+	for _, pair := range pairs {
+		overlayByBase[pair[0]] = pair[1]
+	}
+
+	overlayAttrs := map[string]BodyElement{}
+
+	for _, elem := range overlay.GetElements() {
+		if elem.IsBodyAttribute() {
+			overlayAttrs[elem.GetName()] = elem
+		}
+	}
+
+	merged := NewSignature(base.GetType(), base.GetLabels()...)
+
+	// Block format options are not merged, overlay ones are used if defined
+	formatOpts := overlay.formatOpts
+	if len(formatOpts) == 0 {
+		formatOpts = base.formatOpts
+	}
+
+	if len(formatOpts) > 0 {
+		merged.SetFormatOptions(append([]tokens.GenerateOption{}, formatOpts...)...)
+	}
+
+	for _, elem := range base.GetElements() {
+		newElem, err := mergeElement(path, elem, overlayAttrs, overlayByBase, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		merged.AppendElement(newElem)
+	}
+
+	insertNewAttributes(merged, base, overlay)
+
+	// Append overlay blocks which don't exist in base signature
+	for _, child := range overlayLeft {
+		merged.AppendChild(cloneBlock(child))
+	}
+
+	return merged, nil
+}
+
+// insertNewAttributes inserts overlay attributes which don't exist in base signature after the last base attribute.
+func insertNewAttributes(merged, base, overlay *BlockSignature) {
+	baseAttrs := map[string]bool{}
+	insertAt := 0
+
+	for idx, elem := range base.GetElements() {
+		if elem.IsBodyAttribute() {
+			baseAttrs[elem.GetName()] = true
+			insertAt = idx + 1
+		}
+	}
+
+	newAttrs := BodyElements{}
+
+	for _, elem := range overlay.GetElements() {
+		if elem.IsBodyAttribute() && !baseAttrs[elem.GetName()] {
+			newAttrs = append(newAttrs, elem)
+		}
+	}
+
+	elements := merged.GetElements()
+	merged.SetElements(slices.Concat(elements[:insertAt], newAttrs, elements[insertAt:]))
+}
+
+func mergeElement(
+	path []string,
+	elem BodyElement,
+	overlayAttrs map[string]BodyElement,
+	overlayByBase map[*BlockSignature]*BlockSignature,
+	opts MergeOptions,
+) (BodyElement, error) {
+	switch {
+	case elem.IsBodyAttribute():
+		overlayElem, exists := overlayAttrs[elem.GetName()]
+		if !exists {
+			return elem, nil
+		}
+
+		value, err := mergeValues(
+			strings.Join(append(append([]string{}, path...), elem.GetName()), " > "),
+			*elem.GetBodyAttribute(),
+			*overlayElem.GetBodyAttribute(),
+			opts.Strategy,
+		)
+		if err != nil {
+			return elem, err
+		}
+
+		return NewBodyAttribute(elem.GetName(), value, elem.GetFormatOptions()...), nil
+	case elem.IsBodyBlock():
+		overlayBlock, exists := overlayByBase[elem.GetBodyBlock()]
+		if !exists {
+			return NewBodyBlock(cloneBlock(elem.GetBodyBlock())), nil
+		}
+
+		block, err := mergeBlocks(
+			append(append([]string{}, path...), elem.GetBodyBlock().GetHeader()),
+			elem.GetBodyBlock(),
+			overlayBlock,
+			opts,
+		)
+		if err != nil {
+			return elem, err
+		}
+
+		return NewBodyBlock(block), nil
+	}
+
+	return elem, nil
+}
+
+func mergeValues(path string, base, overlay cty.Value, strategy ConflictStrategy) (cty.Value, error) {
+	if isDeepMergeable(base) && isDeepMergeable(overlay) {
+		if tokens.IsOrderedObjectType(base.Type()) || tokens.IsOrderedObjectType(overlay.Type()) {
+			return deepMergeOrderedValues(path, base, overlay, strategy)
+		}
+
+		return deepMergeValues(path, base, overlay, strategy)
+	}
+
+	if ValuesEqual(base, overlay) {
+		return base, nil
+	}
+
+	switch strategy {
+	case BaseWins:
+		return base, nil
+	case ConflictError:
+		return cty.NilVal, fmt.Errorf("%w: %s is defined with different values", ErrMergeConflict, path)
+	case OverlayWins:
+	}
+
+	return overlay, nil
+}
+
+func deepMergeValues(path string, base, overlay cty.Value, strategy ConflictStrategy) (cty.Value, error) {
+	values := base.AsValueMap()
+	if values == nil {
+		values = map[string]cty.Value{}
+	}
+
+	for key, overlayValue := range overlay.AsValueMap() {
+		baseValue, exists := values[key]
+		if !exists {
+			values[key] = overlayValue
+
+			continue
+		}
+
+		value, err := mergeValues(path+"."+key, baseValue, overlayValue, strategy)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		values[key] = value
+	}
+
+	if base.Type().IsMapType() && overlay.Type().IsMapType() {
+		if len(values) == 0 {
+			return cty.MapValEmpty(base.Type().ElementType()), nil
+		}
+
+		if haveSameType(values) {
+			return cty.MapVal(values), nil
+		}
+	}
+
+	return cty.ObjectVal(values), nil
+}
+
+// haveSameType returns true if all provided values have the same type.
+func haveSameType(values map[string]cty.Value) bool {
+	var elemType *cty.Type
+
+	for _, value := range values {
+		valType := value.Type()
+		if elemType == nil {
+			elemType = &valType
+		} else if !elemType.Equals(valType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func deepMergeOrderedValues(path string, base, overlay cty.Value, strategy ConflictStrategy) (cty.Value, error) {
+	entries := toObjectEntries(base)
+	positions := make(map[string]int, len(entries))
+
+	for idx, entry := range entries {
+		positions[entry.GetKey()] = idx
+	}
+
+	for _, entry := range toObjectEntries(overlay) {
+		pos, exists := positions[entry.GetKey()]
+		if !exists {
+			positions[entry.GetKey()] = len(entries)
+			entries = append(entries, entry)
+
+			continue
+		}
+
+		value, err := mergeValues(path+"."+entry.GetKey(), entries[pos].GetValue(), entry.GetValue(), strategy)
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		entries[pos] = entries[pos].WithValue(value)
+	}
+
+	return tokens.NewOrderedObjectValue(entries...), nil
+}
+
+// toObjectEntries returns a copy of entries of an ordered object, or the sorted entries of an object or a map.
+func toObjectEntries(val cty.Value) []tokens.ObjectEntry {
+	if tokens.IsOrderedObjectType(val.Type()) {
+		return append([]tokens.ObjectEntry{}, tokens.FromOrderedObjectValue(val)...)
+	}
+
+	values := val.AsValueMap()
+	entries := make([]tokens.ObjectEntry, 0, len(values))
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		entries = append(entries, tokens.NewObjectEntry(key, values[key]))
+	}
+
+	return entries
+}
+
+// cloneBlock returns a deep copy of the provided signature (values are immutable and shared).
+func cloneBlock(sig *BlockSignature) *BlockSignature {
+	if sig == nil {
+		return nil
+	}
+
+	clone := NewSignature(sig.GetType(), append([]string{}, sig.GetLabels()...)...)
+	clone.formatOpts = append([]tokens.GenerateOption{}, sig.formatOpts...)
+
+	for _, elem := range sig.GetElements() {
+		if elem.IsBodyBlock() {
+			elem = NewBodyBlock(cloneBlock(elem.GetBodyBlock()))
+		}
+
+		clone.AppendElement(elem)
+	}
+
+	return clone
+}
+
+// isDeepMergeable returns true for known objects, maps and ordered objects, marked ones (e.g. Sensitive) are merged
+// as a whole.
+func isDeepMergeable(val cty.Value) bool {
+	valType := val.Type()
+	isObject := valType.IsObjectType() || valType.IsMapType() || tokens.IsOrderedObjectType(valType)
+
+	return isObject && !val.IsMarked() && val.IsKnown() && !val.IsNull()
+}
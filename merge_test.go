@@ -0,0 +1,394 @@
+package tfsig_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	newBase := func() *tfsig.BlockSignature {
+		sig := tfsig.NewResource("res_name", "res_id")
+		sig.AppendAttribute("attr1", cty.StringVal("base"))
+		sig.AppendAttribute("attr2", cty.StringVal("base"))
+		sig.AppendAttribute("tags", cty.MapVal(map[string]cty.Value{
+			"Name": cty.StringVal("base"),
+			"Env":  cty.StringVal("base"),
+		}))
+		sig.AppendEmptyLine()
+
+		block := tfsig.NewSignature("block1", "A")
+		block.AppendAttribute("attr", cty.StringVal("base"))
+		sig.AppendChild(block)
+
+		return sig
+	}
+	newOverlay := func() *tfsig.BlockSignature {
+		sig := tfsig.NewResource("res_name", "res_id")
+		sig.AppendAttribute("attr3", cty.StringVal("overlay"))
+		sig.AppendAttribute("attr1", *tokens.NewIdentValue("var.overlay"))
+		sig.AppendAttribute("attr2", cty.StringVal("base"))
+		sig.AppendAttribute("tags", cty.MapVal(map[string]cty.Value{
+			"Env":   *tokens.NewIdentValue("var.env"),
+			"Owner": *tokens.NewIdentValue("var.owner"),
+		}))
+
+		block := tfsig.NewSignature("block1", "A")
+		block.AppendAttribute("attr", cty.StringVal("overlay"))
+		block.AppendAttribute("attr2", cty.StringVal("overlay"))
+		sig.AppendChild(block)
+
+		newBlock := tfsig.NewSignature("block1", "B")
+		newBlock.AppendAttribute("attr", cty.StringVal("overlay"))
+		sig.AppendChild(newBlock)
+
+		return sig
+	}
+
+	cases := map[string]struct {
+		opts     tfsig.MergeOptions
+		expected string
+	}{
+		"Overlay wins": {
+			tfsig.MergeOptions{Strategy: tfsig.OverlayWins, BlockKey: nil},
+			`resource "res_name" "res_id" {
+  attr1 = var.overlay
+  attr2 = "base"
+  tags = {
+    "Env"   = var.env
+    "Name"  = "base"
+    "Owner" = var.owner
+  }
+  attr3 = "overlay"
+
+  block1 "A" {
+    attr  = "overlay"
+    attr2 = "overlay"
+  }
+  block1 "B" {
+    attr = "overlay"
+  }
+}
+`,
+		},
+		"Base wins": {
+			tfsig.MergeOptions{Strategy: tfsig.BaseWins, BlockKey: nil},
+			`resource "res_name" "res_id" {
+  attr1 = "base"
+  attr2 = "base"
+  tags = {
+    "Env"   = "base"
+    "Name"  = "base"
+    "Owner" = var.owner
+  }
+  attr3 = "overlay"
+
+  block1 "A" {
+    attr  = "base"
+    attr2 = "overlay"
+  }
+  block1 "B" {
+    attr = "overlay"
+  }
+}
+`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				merged, err := tfsig.Merge(newBase(), newOverlay(), tcase.opts)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", t.Name(), err)
+				}
+
+				hclFile := hclwrite.NewEmptyFile()
+				hclFile.Body().AppendBlock(merged.Build())
+
+				if err = testutils.EnsureFileContentEquals(hclFile, tcase.expected); err != nil {
+					t.Errorf("Case \"%s\": %v", t.Name(), err)
+				}
+			},
+		)
+	}
+}
+
+func TestMerge_blockKey(t *testing.T) {
+	t.Parallel()
+
+	base := tfsig.NewResource("res_name", "res_id")
+	baseBlock := tfsig.NewSignature("block1", "A")
+	baseBlock.AppendAttribute("attr", cty.StringVal("base"))
+	base.AppendChild(baseBlock)
+
+	overlay := tfsig.NewResource("res_name", "res_id")
+	overlayBlock := tfsig.NewSignature("block1", "B")
+	overlayBlock.AppendAttribute("attr2", cty.StringVal("overlay"))
+	overlay.AppendChild(overlayBlock)
+
+	merged, err := tfsig.Merge(base, overlay, tfsig.MergeOptions{
+		Strategy: tfsig.OverlayWins,
+		BlockKey: (*tfsig.BlockSignature).GetType,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(merged.Build())
+
+	expected := `resource "res_name" "res_id" {
+  block1 "A" {
+    attr  = "base"
+    attr2 = "overlay"
+  }
+}
+`
+	if err = testutils.EnsureFileContentEquals(hclFile, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMerge_nil(t *testing.T) {
+	t.Parallel()
+
+	sig := tfsig.NewResource("res_name", "res_id")
+	sig.AppendAttribute("attr", cty.StringVal("value"))
+	sig.AppendChild(tfsig.NewSignature("child"))
+
+	expected := string(hclwrite.Format(sig.BuildTokens().Bytes()))
+
+	cases := map[string]struct {
+		base    *tfsig.BlockSignature
+		overlay *tfsig.BlockSignature
+	}{
+		"Nil overlay": {base: sig, overlay: nil},
+		"Nil base":    {base: nil, overlay: sig},
+	}
+
+	for tcname, tcase := range cases {
+		merged, _ := tfsig.Merge(tcase.base, tcase.overlay, tfsig.MergeOptions{Strategy: tfsig.OverlayWins, BlockKey: nil})
+		if merged == sig || merged.GetElements()[1].GetBodyBlock() == sig.GetElements()[1].GetBodyBlock() {
+			t.Errorf("Case \"%s\": expected a copy of the signature", tcname)
+		}
+
+		if actual := string(hclwrite.Format(merged.BuildTokens().Bytes())); actual != expected {
+			t.Errorf("Case \"%s\": expected\n%s\ngot\n%s", tcname, expected, actual)
+		}
+	}
+
+	if merged, _ := tfsig.Merge(nil, nil, tfsig.MergeOptions{Strategy: tfsig.OverlayWins, BlockKey: nil}); merged != nil {
+		t.Errorf("expected nil, got %v", merged)
+	}
+}
+
+func TestMerge_noAliasing(t *testing.T) {
+	t.Parallel()
+
+	base := tfsig.NewResource("res_name", "res_id")
+	base.AppendChild(tfsig.NewSignature("base_only"))
+
+	overlay := tfsig.NewResource("res_name", "res_id")
+	overlay.AppendChild(tfsig.NewSignature("overlay_only"))
+
+	merged, err := tfsig.Merge(base, overlay, tfsig.MergeOptions{Strategy: tfsig.OverlayWins, BlockKey: nil})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, elem := range merged.GetElements() {
+		elem.GetBodyBlock().AppendAttribute("mutated", cty.True)
+	}
+
+	for _, sig := range []*tfsig.BlockSignature{base, overlay} {
+		if elements := sig.GetElements()[0].GetBodyBlock().GetElements(); len(elements) != 0 {
+			t.Errorf("Case \"%s\": expected source signature to be left untouched", sig.GetElements()[0].GetName())
+		}
+	}
+}
+
+func TestMerge_formatOptions(t *testing.T) {
+	t.Parallel()
+
+	newSig := func(opts ...tokens.GenerateOption) *tfsig.BlockSignature {
+		sig := tfsig.NewResource("res_name", "res_id")
+		sig.AppendAttribute("attr", cty.ListVal([]cty.Value{cty.StringVal("A"), cty.StringVal("B")}))
+		sig.SetFormatOptions(opts...)
+
+		return sig
+	}
+
+	cases := map[string]struct {
+		base     *tfsig.BlockSignature
+		overlay  *tfsig.BlockSignature
+		expected string
+	}{
+		"Base options": {
+			newSig(tokens.WithMultiLine()),
+			newSig(),
+			`resource "res_name" "res_id" {
+  attr = [
+    "A",
+    "B",
+  ]
+}
+`,
+		},
+		"Overlay options": {
+			newSig(),
+			newSig(tokens.WithMultiLine()),
+			`resource "res_name" "res_id" {
+  attr = [
+    "A",
+    "B",
+  ]
+}
+`,
+		},
+		"Overlay options win": {
+			newSig(tokens.WithMultiLine()),
+			newSig(tokens.WithMaxElements(3)),
+			`resource "res_name" "res_id" {
+  attr = ["A", "B"]
+}
+`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				opts := tfsig.MergeOptions{Strategy: tfsig.OverlayWins, BlockKey: nil}
+
+				merged, err := tfsig.Merge(tcase.base, tcase.overlay, opts)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", t.Name(), err)
+				}
+
+				hclFile := hclwrite.NewEmptyFile()
+				hclFile.Body().AppendBlock(merged.Build())
+
+				if err = testutils.EnsureFileContentEquals(hclFile, tcase.expected); err != nil {
+					t.Errorf("Case \"%s\": %v", t.Name(), err)
+				}
+			},
+		)
+	}
+}
+
+func TestMerge_error(t *testing.T) {
+	t.Parallel()
+
+	base := tfsig.NewResource("res_name", "res_id")
+	base.AppendAttribute("tags", cty.ObjectVal(map[string]cty.Value{"Name": cty.StringVal("base")}))
+
+	overlay := tfsig.NewResource("res_name", "res_id")
+	overlay.AppendAttribute("tags", cty.ObjectVal(map[string]cty.Value{"Name": cty.StringVal("overlay")}))
+
+	cases := map[string]struct {
+		base          *tfsig.BlockSignature
+		overlay       *tfsig.BlockSignature
+		expectedError error
+		expectedMsg   string
+	}{
+		"Conflict": {
+			base,
+			overlay,
+			tfsig.ErrMergeConflict,
+			`merge conflict: resource "res_name" "res_id" > tags.Name is defined with different values`,
+		},
+		"Type mismatch": {
+			base,
+			tfsig.NewSignature("data"),
+			tfsig.ErrMergeTypeMismatch,
+			`unable to merge blocks with different types: "resource" and "data"`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				_, err := tfsig.Merge(tcase.base, tcase.overlay, tfsig.MergeOptions{Strategy: tfsig.ConflictError, BlockKey: nil})
+				if !errors.Is(err, tcase.expectedError) {
+					t.Fatalf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expectedError, err)
+				}
+
+				if err.Error() != tcase.expectedMsg {
+					t.Errorf("Case \"%s\": expected message %q, got %q", t.Name(), tcase.expectedMsg, err.Error())
+				}
+			},
+		)
+	}
+}
+
+func TestMerge_orderedObject(t *testing.T) {
+	t.Parallel()
+
+	base := tfsig.NewResource("res_name", "res_id")
+	base.AppendAttribute("tags", tokens.NewOrderedObjectValue(
+		tokens.NewObjectEntry("Name", cty.StringVal("base")),
+		tokens.NewObjectEntry("Env", cty.StringVal("base")),
+	))
+	base.AppendAttribute("labels", cty.MapVal(map[string]cty.Value{
+		"b": cty.StringVal("base"),
+		"a": cty.StringVal("base"),
+	}))
+
+	overlay := tfsig.NewResource("res_name", "res_id")
+	overlay.AppendAttribute("tags", cty.ObjectVal(map[string]cty.Value{
+		"Owner": *tokens.NewIdentValue("var.owner"),
+		"Env":   *tokens.NewIdentValue("var.env"),
+	}))
+	overlay.AppendAttribute("labels", tokens.NewOrderedObjectValue(
+		tokens.NewObjectEntry("c", cty.StringVal("overlay")),
+		tokens.NewObjectEntry("a", cty.StringVal("overlay")),
+	))
+
+	merged, err := tfsig.Merge(base, overlay, tfsig.MergeOptions{Strategy: tfsig.OverlayWins, BlockKey: nil})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(merged.Build())
+
+	expected := `resource "res_name" "res_id" {
+  tags = {
+    Name  = "base"
+    Env   = var.env
+    Owner = var.owner
+  }
+  labels = {
+    a = "overlay"
+    b = "base"
+    c = "overlay"
+  }
+}
+`
+	if err = testutils.EnsureFileContentEquals(hclFile, expected); err != nil {
+		t.Error(err)
+	}
+
+	entries := tokens.FromOrderedObjectValue(*base.GetElements()[0].GetBodyAttribute())
+	if entries[1].GetValue().AsString() != "base" {
+		t.Error("expected base value to be left untouched")
+	}
+}
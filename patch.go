@@ -0,0 +1,347 @@
+package tfsig
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// PatchOptions is used as argument for `PatchFile()` function.
+type PatchOptions struct {
+	// Prune removes attributes and nested blocks of patched blocks which are not part of the related signature
+	Prune bool
+	// IsOwned reports whether a top-level block of the file is owned by the generator.
+	// Owned blocks which don't match any of the provided signatures are removed from the file.
+	// Nothing is removed if nil.
+	IsOwned func(block *hclwrite.Block) bool
+}
+
+// PatchFile upserts provided signatures into an existing `hclwrite.File`
+//
+// Top-level blocks are identified by their type and labels. Existing blocks are patched in place: only attributes
+// with a different value are rewritten, new attributes are inserted after the last existing attribute and new nested
+// blocks are appended. Everything else (unrelated blocks, comments and whitespaces) is left untouched.
+// New blocks are appended at the end of the file, removed blocks are removed alongside their trailing empty line.
+//
+// Keep in mind that `hclwrite.File.Bytes()` always applies the canonical formatting to the whole file.
+func PatchFile(file *hclwrite.File, sigs []*BlockSignature, opts PatchOptions) {
+	body := file.Body()
+	used := map[*hclwrite.Block]bool{}
+
+	for _, sig := range sigs {
+		if sig == nil {
+			continue
+		}
+
+		if block := findUnusedBlock(body, sig, used); block != nil {
+			used[block] = true
+
+			patchBody(block.Body(), sig.GetElements(), sig.formatOpts, 1, opts)
+
+			continue
+		}
+
+		if len(body.Attributes()) > 0 || len(body.Blocks()) > 0 {
+			body.AppendNewline()
+		}
+
+		used[body.AppendBlock(sig.Build())] = true
+	}
+
+	if opts.IsOwned != nil {
+		removed := []*hclwrite.Block{}
+
+		for _, block := range body.Blocks() {
+			if !used[block] && opts.IsOwned(block) {
+				removed = append(removed, block)
+			}
+		}
+
+		rewriteBody(body, nil, removed)
+	}
+}
+
+/** Private **/
+
+func patchBody(
+	body *hclwrite.Body,
+	elements BodyElements,
+	formatOpts []tokens.GenerateOption,
+	depth int,
+	opts PatchOptions,
+) {
+	usedBlocks := map[*hclwrite.Block]bool{}
+	attrNames := map[string]bool{}
+	newAttrs := hclwrite.NewEmptyFile().Body()
+
+	for _, elem := range elements {
+		switch {
+		case elem.IsBodyAttribute():
+			attrNames[elem.GetName()] = true
+
+			attrOpts := mergeFormatOptions(formatOpts, elem.formatOpts)
+			expected := generateAttributeTokens(elem.GetName(), elem.attr, attrOpts, depth)
+
+			existing := body.GetAttribute(elem.GetName())
+			if existing == nil {
+				writeAttributeToBody(newAttrs, elem.GetName(), elem.attr, attrOpts, depth)
+			} else if !exprEquals(existing.Expr().BuildTokens(nil), expected) {
+				writeAttributeToBody(body, elem.GetName(), elem.attr, attrOpts, depth)
+			}
+		case elem.IsBodyBlock():
+			child := elem.GetBodyBlock()
+			if block := findUnusedBlock(body, child, usedBlocks); block != nil {
+				usedBlocks[block] = true
+
+				patchBody(
+					block.Body(),
+					child.GetElements(),
+					mergeFormatOptions(formatOpts, child.formatOpts),
+					depth+1,
+					opts,
+				)
+			} else {
+				usedBlocks[body.AppendBlock(child.build(formatOpts, depth))] = true
+			}
+		}
+	}
+
+	removed := []*hclwrite.Block{}
+	if opts.Prune {
+		removed = pruneBody(body, attrNames, usedBlocks)
+	}
+
+	rewriteBody(body, newAttrs.BuildTokens(nil), removed)
+}
+
+// pruneBody removes attributes not listed in provided names and returns blocks which have not been used.
+func pruneBody(body *hclwrite.Body, attrNames map[string]bool, usedBlocks map[*hclwrite.Block]bool) []*hclwrite.Block {
+	removed := []*hclwrite.Block{}
+
+	for name := range body.Attributes() {
+		if !attrNames[name] {
+			body.RemoveAttribute(name)
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		if !usedBlocks[block] {
+			removed = append(removed, block)
+		}
+	}
+
+	return removed
+}
+
+// rewriteBody inserts provided attribute tokens after the last attribute of the body (at the beginning if there is
+// none), and removes provided blocks alongside their trailing empty line (or their leading one for the last block)
+//
+// Body content is replaced by its re-parsed version, so previously fetched attributes and blocks of the body must not
+// be used afterward.
+func rewriteBody(body *hclwrite.Body, attrTokens hclwrite.Tokens, removed []*hclwrite.Block) {
+	if len(attrTokens) == 0 && len(removed) == 0 {
+		return
+	}
+
+	bodyTokens := body.BuildTokens(nil)
+	positions := make(map[*hclwrite.Token]int, len(bodyTokens))
+
+	for idx, token := range bodyTokens {
+		positions[token] = idx
+	}
+
+	insertAt := 0
+
+	for _, attr := range body.Attributes() {
+		if tokens := attr.BuildTokens(nil); len(tokens) > 0 {
+			insertAt = max(insertAt, positions[tokens[len(tokens)-1]]+1)
+		}
+	}
+
+	skipped := removedPositions(bodyTokens, positions, removed)
+	newTokens := make(hclwrite.Tokens, 0, len(bodyTokens)+len(attrTokens))
+
+	for idx, token := range bodyTokens {
+		if idx == insertAt {
+			newTokens = append(newTokens, attrTokens...)
+		}
+
+		if !skipped[idx] {
+			newTokens = append(newTokens, token)
+		}
+	}
+
+	if insertAt >= len(bodyTokens) {
+		newTokens = append(newTokens, attrTokens...)
+	}
+
+	replaceBodyContent(body, newTokens)
+}
+
+// removedPositions returns positions of tokens of provided blocks, alongside their trailing empty line (or their
+// leading one for the last block).
+func removedPositions(
+	bodyTokens hclwrite.Tokens,
+	positions map[*hclwrite.Token]int,
+	removed []*hclwrite.Block,
+) map[int]bool {
+	skipped := map[int]bool{}
+
+	for _, block := range removed {
+		blockTokens := block.BuildTokens(nil)
+		start, end := positions[blockTokens[0]], positions[blockTokens[len(blockTokens)-1]]
+
+		for idx := start; idx <= end; idx++ {
+			skipped[idx] = true
+		}
+
+		switch {
+		case end+1 < len(bodyTokens) && isNewline(bodyTokens[end+1]):
+			skipped[end+1] = true
+		case start > 1 && isNewline(bodyTokens[start-1]) && isNewline(bodyTokens[start-2]):
+			skipped[start-1] = true
+		}
+	}
+
+	return skipped
+}
+
+// replaceBodyContent replaces the body content by provided tokens
+//
+// Tokens are re-parsed in order to keep the body structured: parsed blocks are moved as is into the body and
+// attributes are re-created with their expression (lead comments are kept as unstructured tokens).
+func replaceBodyContent(body *hclwrite.Body, content hclwrite.Tokens) {
+	// Clear() only removes tokens, attributes and blocks must be removed explicitly
+	for name := range body.Attributes() {
+		body.RemoveAttribute(name)
+	}
+
+	for _, block := range body.Blocks() {
+		body.RemoveBlock(block)
+	}
+
+	body.Clear()
+
+	parsed, diags := hclwrite.ParseConfig(content.Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		// Should not happen as content comes from valid tokens, keep it as is anyway
+		body.AppendUnstructuredTokens(content)
+
+		return
+	}
+
+	parsedBody := parsed.Body()
+	items := map[*hclwrite.Token]bodyItem{}
+
+	for _, block := range parsedBody.Blocks() {
+		items[block.BuildTokens(nil)[0]] = bodyItem{
+			size: len(block.BuildTokens(nil)),
+			appendTo: func(body *hclwrite.Body) {
+				parsedBody.RemoveBlock(block)
+				body.AppendBlock(block)
+			},
+		}
+	}
+
+	for name, attr := range parsedBody.Attributes() {
+		items[attr.BuildTokens(nil)[0]] = bodyItem{
+			size: len(attr.BuildTokens(nil)),
+			appendTo: func(body *hclwrite.Body) {
+				appendParsedAttribute(body, name, attr)
+			},
+		}
+	}
+
+	unstructured := hclwrite.Tokens{}
+	parsedTokens := parsedBody.BuildTokens(nil)
+
+	for idx := 0; idx < len(parsedTokens); {
+		item, exists := items[parsedTokens[idx]]
+		if !exists {
+			unstructured = append(unstructured, parsedTokens[idx])
+			idx++
+
+			continue
+		}
+
+		if len(unstructured) > 0 {
+			body.AppendUnstructuredTokens(unstructured)
+			unstructured = hclwrite.Tokens{}
+		}
+
+		item.appendTo(body)
+		idx += item.size
+	}
+
+	if len(unstructured) > 0 {
+		body.AppendUnstructuredTokens(unstructured)
+	}
+}
+
+// bodyItem is an attribute or a block of a parsed body.
+type bodyItem struct {
+	size     int
+	appendTo func(body *hclwrite.Body)
+}
+
+// appendParsedAttribute appends the lead comments of the attribute followed by a new attribute holding its expression
+// (line comments are appended to the expression).
+func appendParsedAttribute(body *hclwrite.Body, name string, attr *hclwrite.Attribute) {
+	attrTokens := attr.BuildTokens(nil)
+	exprTokens := attr.Expr().BuildTokens(nil)
+
+	exprStart := slices.Index(attrTokens, exprTokens[0])
+
+	// Lead comments are located before the attribute name
+	if nameStart := slices.IndexFunc(attrTokens, isIdent); nameStart > 0 {
+		body.AppendUnstructuredTokens(attrTokens[:nameStart])
+	}
+
+	for _, token := range attrTokens[exprStart+len(exprTokens):] {
+		if token.Type == hclsyntax.TokenComment {
+			exprTokens = append(exprTokens, &hclwrite.Token{
+				Type:         token.Type,
+				Bytes:        bytes.TrimRight(token.Bytes, "\r\n"),
+				SpacesBefore: token.SpacesBefore,
+			})
+		}
+	}
+
+	body.SetAttributeRaw(name, exprTokens)
+}
+
+func isNewline(token *hclwrite.Token) bool {
+	return token.Type == hclsyntax.TokenNewline
+}
+
+func isIdent(token *hclwrite.Token) bool {
+	return token.Type == hclsyntax.TokenIdent
+}
+
+func findUnusedBlock(body *hclwrite.Body, sig *BlockSignature, used map[*hclwrite.Block]bool) *hclwrite.Block {
+	for _, block := range body.Blocks() {
+		if !used[block] && block.Type() == sig.GetType() && slices.Equal(block.Labels(), sig.GetLabels()) {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// exprEquals compares two expressions based on their formatted bytes, comments are ignored.
+func exprEquals(a, b hclwrite.Tokens) bool {
+	return bytes.Equal(formatExpr(a), formatExpr(b))
+}
+
+func formatExpr(expr hclwrite.Tokens) []byte {
+	expr = slices.DeleteFunc(slices.Clone(expr), func(token *hclwrite.Token) bool {
+		return token.Type == hclsyntax.TokenComment
+	})
+
+	return bytes.TrimSpace(hclwrite.Format(expr.Bytes()))
+}
@@ -0,0 +1,163 @@
+package tfsig_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+const patchFileContent = `# Hand-written header comment
+locals {
+  foo = "bar" # hand-written
+}
+
+# Generated block
+resource "res_name" "res_id" {
+  # attribute comment
+  attribute1 = "value1" # line comment
+  attribute2 = var.foo
+
+  attribute3 = ["A", "B"]
+
+  block1 "label" {
+    attribute11 = "old"
+  }
+  hand_written = true
+}
+
+resource "res_name" "stale" {
+  attribute1 = "value1"
+}
+`
+
+func TestPatchFile(t *testing.T) {
+	t.Parallel()
+
+	newSigs := func() []*tfsig.BlockSignature {
+		sig := tfsig.NewResource("res_name", "res_id")
+		sig.AppendAttribute("attribute1", cty.StringVal("value1"))
+		sig.AppendAttribute("attribute2", *tokens.NewIdentValue("var.bar"))
+		sig.AppendAttribute("attribute3", cty.ListVal([]cty.Value{cty.StringVal("A"), cty.StringVal("B")}))
+		sig.AppendAttribute("attribute4", cty.StringVal("new"))
+
+		block1 := tfsig.NewSignature("block1", "label")
+		block1.AppendAttribute("attribute11", cty.StringVal("new"))
+		sig.AppendChild(block1)
+
+		block2 := tfsig.NewSignature("block2")
+		block2.AppendAttribute("attribute21", cty.True)
+		sig.AppendChild(block2)
+
+		newSig := tfsig.NewResource("res_name", "new")
+		newSig.AppendAttribute("attribute1", cty.StringVal("value1"))
+
+		return []*tfsig.BlockSignature{sig, newSig}
+	}
+
+	cases := map[string]struct {
+		opts     tfsig.PatchOptions
+		expected string
+	}{
+		"Default": {
+			tfsig.PatchOptions{Prune: false, IsOwned: nil},
+			`# Hand-written header comment
+locals {
+  foo = "bar" # hand-written
+}
+
+# Generated block
+resource "res_name" "res_id" {
+  # attribute comment
+  attribute1 = "value1" # line comment
+  attribute2 = var.bar
+
+  attribute3 = ["A", "B"]
+
+  block1 "label" {
+    attribute11 = "new"
+  }
+  hand_written = true
+  attribute4   = "new"
+  block2 {
+    attribute21 = true
+  }
+}
+
+resource "res_name" "stale" {
+  attribute1 = "value1"
+}
+
+resource "res_name" "new" {
+  attribute1 = "value1"
+}
+`,
+		},
+		"Prune and remove owned blocks": {
+			tfsig.PatchOptions{
+				Prune: true,
+				IsOwned: func(block *hclwrite.Block) bool {
+					return block.Type() == "resource"
+				},
+			},
+			`# Hand-written header comment
+locals {
+  foo = "bar" # hand-written
+}
+
+# Generated block
+resource "res_name" "res_id" {
+  # attribute comment
+  attribute1 = "value1" # line comment
+  attribute2 = var.bar
+
+  attribute3 = ["A", "B"]
+  attribute4 = "new"
+
+  block1 "label" {
+    attribute11 = "new"
+  }
+  block2 {
+    attribute21 = true
+  }
+}
+
+resource "res_name" "new" {
+  attribute1 = "value1"
+}
+`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				file, diags := hclwrite.ParseConfig([]byte(patchFileContent), "main.tf", hcl.InitialPos)
+				if diags.HasErrors() {
+					t.Fatalf("Case \"%s\": %v", t.Name(), diags)
+				}
+
+				tfsig.PatchFile(file, newSigs(), tcase.opts)
+
+				if err := testutils.EnsureFileContentEquals(file, tcase.expected); err != nil {
+					t.Errorf("Case \"%s\": %v", t.Name(), err)
+				}
+
+				// Patching an already patched file must not change anything
+				tfsig.PatchFile(file, newSigs(), tcase.opts)
+
+				if err := testutils.EnsureFileContentEquals(file, tcase.expected); err != nil {
+					t.Errorf("Case \"%s\": second patch: %v", t.Name(), err)
+				}
+			},
+		)
+	}
+}
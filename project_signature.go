@@ -0,0 +1,169 @@
+package tfsig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+)
+
+// NewProjectSignature returns a ProjectSignature pointer using the provided router
+//
+// Default router (see `NewDefaultFileRouter()`) is used if nil.
+func NewProjectSignature(router FileRouter) *ProjectSignature {
+	if router == nil {
+		router = NewDefaultFileRouter()
+	}
+
+	return &ProjectSignature{router: router, files: map[string]*FileSignature{}, order: []string{}}
+}
+
+// ProjectSignature holds blocks of a whole terraform module, split into several files.
+type ProjectSignature struct {
+	router FileRouter
+	files  map[string]*FileSignature
+	order  []string
+}
+
+// ProjectWriteOptions is used as argument for `ProjectSignature.Write()` method.
+type ProjectWriteOptions struct {
+	// IsGenerated reports whether an existing `.tf` file of the directory is owned by the generator.
+	// Owned files which are not produced anymore are removed. Nothing is removed if nil.
+	IsGenerated func(name string, content []byte) bool
+}
+
+// AppendBlock appends a block to the file returned by the router (`main.tf` if router returns an empty filename).
+func (p *ProjectSignature) AppendBlock(block *BlockSignature) {
+	filename := p.router.Route(block)
+	if filename == "" {
+		filename = DefaultMainFilename
+	}
+
+	p.AppendBlockTo(filename, block)
+}
+
+// AppendBlockTo appends a block to the provided file, bypassing the router.
+func (p *ProjectSignature) AppendBlockTo(filename string, block *BlockSignature) {
+	p.GetFile(filename).AppendBlock(block)
+}
+
+// GetFile returns the file signature for the provided filename, it is created if it doesn't exist yet.
+func (p *ProjectSignature) GetFile(filename string) *FileSignature {
+	file, exists := p.files[filename]
+	if !exists {
+		file = NewFileSignature()
+		p.files[filename] = file
+		p.order = append(p.order, filename)
+	}
+
+	return file
+}
+
+// GetFiles returns all file signatures indexed by filename.
+func (p *ProjectSignature) GetFiles() map[string]*FileSignature {
+	files := make(map[string]*FileSignature, len(p.files))
+	for name, file := range p.files {
+		files[name] = file
+	}
+
+	return files
+}
+
+// GetFilenames returns filenames in creation order.
+func (p *ProjectSignature) GetFilenames() []string {
+	return append([]string{}, p.order...)
+}
+
+// Render returns the content of each file indexed by filename.
+func (p *ProjectSignature) Render() map[string][]byte {
+	contents := make(map[string][]byte, len(p.files))
+	for name, file := range p.files {
+		contents[name] = file.Bytes()
+	}
+
+	return contents
+}
+
+// Check compares generated files with files located under the provided directory (see `CheckDir()`).
+func (p *ProjectSignature) Check(dir string) ([]FileDrift, error) {
+	return CheckDir(dir, p.files)
+}
+
+// Write writes generated files to the provided file system, only files whose content actually changes are written
+//
+// It returns the list of written files and the list of removed files (see `ProjectWriteOptions.IsGenerated`).
+func (p *ProjectSignature) Write(fsys WriteFS, opts ProjectWriteOptions) (
+	/* written */ []string,
+	/* removed */ []string,
+	error,
+) {
+	written, removed := []string{}, []string{}
+
+	for _, name := range slices.Sorted(maps.Keys(p.files)) {
+		content, err := p.files[name].SafeBytes()
+		if err != nil {
+			return written, removed, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+
+		actual, err := fs.ReadFile(fsys, name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return written, removed, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if err == nil && bytes.Equal(actual, content) {
+			continue
+		}
+
+		if err = fsys.WriteFile(name, content); err != nil {
+			return written, removed, err //nolint:wrapcheck // WriteFS implementations already report the filename
+		}
+
+		written = append(written, name)
+	}
+
+	if opts.IsGenerated == nil {
+		return written, removed, nil
+	}
+
+	removed, err := p.removeStaleFiles(fsys, opts.IsGenerated)
+
+	return written, removed, err
+}
+
+/** Private **/
+
+func (p *ProjectSignature) removeStaleFiles(fsys WriteFS, isGenerated func(string, []byte) bool) ([]string, error) {
+	removed := []string{}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return removed, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if _, exists := p.files[name]; exists || entry.IsDir() || path.Ext(name) != ".tf" {
+			continue
+		}
+
+		content, readErr := fs.ReadFile(fsys, name)
+		if readErr != nil {
+			return removed, fmt.Errorf("failed to read %s: %w", name, readErr)
+		}
+
+		if !isGenerated(name, content) {
+			continue
+		}
+
+		if err = fsys.Remove(name); err != nil {
+			return removed, err //nolint:wrapcheck // WriteFS implementations already report the filename
+		}
+
+		removed = append(removed, name)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,186 @@
+package tfsig_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func newTestProject(router tfsig.FileRouter) *tfsig.ProjectSignature {
+	project := tfsig.NewProjectSignature(router)
+
+	project.AppendBlock(tfsig.NewSignature("terraform"))
+	project.AppendBlock(tfsig.NewSignature("variable", "name"))
+	project.AppendBlock(tfsig.NewResource("res_name", "res_id"))
+	project.AppendBlock(tfsig.NewResource("other_res_name", "res_id"))
+	project.AppendBlock(tfsig.NewSignature("output", "id"))
+	project.AppendBlockTo("explicit.tf", tfsig.NewSignature("locals"))
+
+	return project
+}
+
+func TestProjectSignature_routing(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		router   tfsig.FileRouter
+		expected map[string][]string
+	}{
+		"Default router": {
+			nil,
+			map[string][]string{
+				"versions.tf":  {"terraform"},
+				"variables.tf": {`variable "name"`},
+				"main.tf":      {`resource "res_name" "res_id"`, `resource "other_res_name" "res_id"`},
+				"outputs.tf":   {`output "id"`},
+				"explicit.tf":  {"locals"},
+			},
+		},
+		"Chained routers": {
+			tfsig.ChainRouters(
+				tfsig.RouteByLabel(map[string]string{"other_res_name": "other.tf"}),
+				tfsig.NewDefaultFileRouter(),
+			),
+			map[string][]string{
+				"versions.tf":  {"terraform"},
+				"variables.tf": {`variable "name"`},
+				"main.tf":      {`resource "res_name" "res_id"`},
+				"other.tf":     {`resource "other_res_name" "res_id"`},
+				"outputs.tf":   {`output "id"`},
+				"explicit.tf":  {"locals"},
+			},
+		},
+		"Chained routers without fallback": {
+			tfsig.ChainRouters(
+				tfsig.RouteByType(map[string]string{"variable": "variables.tf"}, ""),
+				tfsig.RouteByLabel(map[string]string{"other_res_name": "other.tf"}),
+			),
+			map[string][]string{
+				"variables.tf": {`variable "name"`},
+				"main.tf":      {"terraform", `resource "res_name" "res_id"`, `output "id"`},
+				"other.tf":     {`resource "other_res_name" "res_id"`},
+				"explicit.tf":  {"locals"},
+			},
+		},
+		"Empty route": {
+			tfsig.RouteByLabel(map[string]string{}),
+			map[string][]string{
+				"main.tf": {
+					"terraform",
+					`variable "name"`,
+					`resource "res_name" "res_id"`,
+					`resource "other_res_name" "res_id"`,
+					`output "id"`,
+				},
+				"explicit.tf": {"locals"},
+			},
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				actual := map[string][]string{}
+
+				for name, content := range newTestProject(tcase.router).Render() {
+					for _, line := range strings.Split(string(content), "\n") {
+						if header, found := strings.CutSuffix(line, " {"); found {
+							actual[name] = append(actual[name], header)
+						}
+					}
+				}
+
+				if !reflect.DeepEqual(tcase.expected, actual) {
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestProjectSignature_Write(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fsys := tfsig.NewDirFS(dir)
+	generatedHeader := []byte("# generated\n")
+
+	writeTestFiles(t, dir, map[string][]byte{
+		"stale.tf":        generatedHeader,
+		"hand_written.tf": []byte("locals {}\n"),
+		"README.md":       generatedHeader,
+	})
+
+	project := newTestProject(nil)
+	opts := tfsig.ProjectWriteOptions{
+		IsGenerated: func(_ string, content []byte) bool {
+			return bytes.HasPrefix(content, generatedHeader)
+		},
+	}
+
+	written, removed, err := project.Write(fsys, opts)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedWritten := []string{"explicit.tf", "main.tf", "outputs.tf", "variables.tf", "versions.tf"}
+	if !reflect.DeepEqual(expectedWritten, written) {
+		t.Errorf("expected written files %v, got %v", expectedWritten, written)
+	}
+
+	if !reflect.DeepEqual([]string{"stale.tf"}, removed) {
+		t.Errorf("expected removed files %v, got %v", []string{"stale.tf"}, removed)
+	}
+
+	if drifts, _ := project.Check(dir); len(drifts) != 0 {
+		t.Errorf("expected no drift, got %v", drifts)
+	}
+
+	// Second write must only touch updated file
+	project.AppendBlock(tfsig.NewResource("res_name", "new_res_id"))
+
+	if written, removed, err = project.Write(fsys, opts); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !reflect.DeepEqual([]string{"main.tf"}, written) || len(removed) != 0 {
+		t.Errorf("expected only main.tf to be written, got %v written and %v removed", written, removed)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 7 {
+		t.Errorf("expected 7 files, got %d", len(files))
+	}
+
+	assertFileRemoved(t, fsys, dir, "stale.tf")
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func assertFileRemoved(t *testing.T, fsys tfsig.WriteFS, dir, name string) {
+	t.Helper()
+
+	if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", name, err)
+	}
+
+	if err := fsys.Remove(name); err == nil {
+		t.Errorf("expected an error when removing a non-existing file")
+	}
+}
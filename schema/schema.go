@@ -0,0 +1,214 @@
+/*
+Package schema provides a way to validate signatures against provider schemas, as returned by
+`terraform providers schema -json` command
+
+It allows to catch typos in attribute names, missing required attributes or wrong literal values
+before running `terraform plan`
+*/
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ErrInvalidSchema is returned by `Load()` and `Parse()` when the document can't be decoded.
+var ErrInvalidSchema = errors.New("invalid schema document")
+
+// NestingMode is the nesting mode of a nested block or of a nested attribute type.
+type NestingMode string
+
+const (
+	// NestingSingle allows at most one nested block (or a single object for nested attribute types).
+	NestingSingle NestingMode = "single"
+	// NestingGroup behaves like NestingSingle, except that the block is never null.
+	NestingGroup NestingMode = "group"
+	// NestingList allows any number of nested blocks, in order.
+	NestingList NestingMode = "list"
+	// NestingSet allows any number of unique nested blocks.
+	NestingSet NestingMode = "set"
+	// NestingMap allows any number of nested blocks, each one having a single label used as map key.
+	NestingMap NestingMode = "map"
+)
+
+// ProvidersSchema is the document returned by `terraform providers schema -json` command.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type ProvidersSchema struct {
+	FormatVersion   string                     `json:"format_version"`
+	ProviderSchemas map[string]*ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema holds schemas of a provider, keyed by resource or data source type.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type ProviderSchema struct {
+	Provider          *Schema            `json:"provider"`
+	ResourceSchemas   map[string]*Schema `json:"resource_schemas"`
+	DataSourceSchemas map[string]*Schema `json:"data_source_schemas"`
+}
+
+// Schema is the schema of a provider configuration, a resource or a data source.
+type Schema struct {
+	Version int64  `json:"version"`
+	Block   *Block `json:"block"`
+}
+
+// Block is the schema of a block, attributes and nested blocks are keyed by name.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type Block struct {
+	Attributes      map[string]*Attribute   `json:"attributes"`
+	BlockTypes      map[string]*NestedBlock `json:"block_types"`
+	Description     string                  `json:"description"`
+	DescriptionKind string                  `json:"description_kind"`
+	Deprecated      bool                    `json:"deprecated"`
+}
+
+// Attribute is the schema of an attribute, either Type or NestedType is defined.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type Attribute struct {
+	Type       cty.Type             `json:"type"`
+	NestedType *NestedAttributeType `json:"nested_type"`
+	Required   bool                 `json:"required"`
+	Optional   bool                 `json:"optional"`
+	Computed   bool                 `json:"computed"`
+	Sensitive  bool                 `json:"sensitive"`
+
+	Description     string `json:"description"`
+	DescriptionKind string `json:"description_kind"`
+	Deprecated      bool   `json:"deprecated"`
+}
+
+// NestedAttributeType is the schema of an attribute whose value is an object (or a collection of objects).
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type NestedAttributeType struct {
+	Attributes  map[string]*Attribute `json:"attributes"`
+	NestingMode NestingMode           `json:"nesting_mode"`
+}
+
+// NestedBlock is the schema of a nested block.
+//
+//nolint:tagliatelle // Field names are defined by terraform
+type NestedBlock struct {
+	Block       *Block      `json:"block"`
+	NestingMode NestingMode `json:"nesting_mode"`
+	MinItems    int         `json:"min_items"`
+	MaxItems    int         `json:"max_items"`
+}
+
+// Load reads and decodes the schema document located at the provided path (see `Parse()`).
+func Load(path string) (*ProvidersSchema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return Parse(content)
+}
+
+// Parse decodes the provided `terraform providers schema -json` output.
+func Parse(content []byte) (*ProvidersSchema, error) {
+	doc := &ProvidersSchema{FormatVersion: "", ProviderSchemas: nil}
+	if err := json.Unmarshal(content, doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+
+	return doc, nil
+}
+
+// ResourceSchema returns the schema of the provided resource type and whether it exists.
+func (s *ProvidersSchema) ResourceSchema(resourceType string) (*Schema, bool) {
+	for _, address := range s.providerAddresses() {
+		if schema, exists := s.ProviderSchemas[address].ResourceSchemas[resourceType]; exists {
+			return schema, true
+		}
+	}
+
+	return nil, false
+}
+
+// DataSourceSchema returns the schema of the provided data source type and whether it exists.
+func (s *ProvidersSchema) DataSourceSchema(dataSourceType string) (*Schema, bool) {
+	for _, address := range s.providerAddresses() {
+		if schema, exists := s.ProviderSchemas[address].DataSourceSchemas[dataSourceType]; exists {
+			return schema, true
+		}
+	}
+
+	return nil, false
+}
+
+// ProviderConfigSchema returns the configuration schema of the provided provider and whether it exists
+//
+// Provider can be either a local name (e.g. `aws`) or a full address (e.g. `registry.terraform.io/hashicorp/aws`).
+func (s *ProvidersSchema) ProviderConfigSchema(provider string) (*Schema, bool) {
+	for _, address := range s.providerAddresses() {
+		if address == provider || address[strings.LastIndex(address, "/")+1:] == provider {
+			schema := s.ProviderSchemas[address].Provider
+
+			return schema, schema != nil
+		}
+	}
+
+	return nil, false
+}
+
+// ImpliedType returns the type of the attribute value, computed from NestedType if defined
+//
+// Attributes of nested attribute types which are not required are optional.
+func (a *Attribute) ImpliedType() cty.Type {
+	if a.NestedType == nil {
+		return a.Type
+	}
+
+	attrTypes := map[string]cty.Type{}
+	optional := []string{}
+
+	for name, attr := range a.NestedType.Attributes {
+		attrTypes[name] = attr.ImpliedType()
+
+		if !attr.Required {
+			optional = append(optional, name)
+		}
+	}
+
+	objType := cty.ObjectWithOptionalAttrs(attrTypes, optional)
+
+	switch a.NestedType.NestingMode {
+	case NestingList:
+		return cty.List(objType)
+	case NestingSet:
+		return cty.Set(objType)
+	case NestingMap:
+		return cty.Map(objType)
+	case NestingSingle, NestingGroup:
+	}
+
+	return objType
+}
+
+/** Private **/
+
+// providerAddresses returns sorted provider addresses, so that lookups are deterministic.
+func (s *ProvidersSchema) providerAddresses() []string {
+	addresses := make([]string, 0, len(s.ProviderSchemas))
+
+	for address, schema := range s.ProviderSchemas {
+		if schema != nil {
+			addresses = append(addresses, address)
+		}
+	}
+
+	sort.Strings(addresses)
+
+	return addresses
+}
@@ -0,0 +1,108 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/schema"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	doc, err := schema.Load("testdata/providers.schema.json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, exists := doc.ResourceSchema("aws_instance"); !exists {
+		t.Errorf("expected aws_instance resource schema")
+	}
+
+	if _, exists := doc.ResourceSchema("aws_ami"); exists {
+		t.Errorf("unexpected aws_ami resource schema")
+	}
+
+	if _, exists := doc.DataSourceSchema("aws_ami"); !exists {
+		t.Errorf("expected aws_ami data source schema")
+	}
+
+	for _, name := range []string{"aws", "registry.terraform.io/hashicorp/aws"} {
+		if _, exists := doc.ProviderConfigSchema(name); !exists {
+			t.Errorf("expected %s provider schema", name)
+		}
+	}
+
+	if _, exists := doc.ProviderConfigSchema("google"); exists {
+		t.Errorf("unexpected google provider schema")
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := schema.Parse([]byte(`{"provider_schemas": []}`)); !errors.Is(err, schema.ErrInvalidSchema) {
+		t.Errorf("expected %v, got %v", schema.ErrInvalidSchema, err)
+	}
+
+	if _, err := schema.Load("testdata/unknown.json"); err == nil {
+		t.Errorf("expected an error for an unknown file")
+	}
+}
+
+func TestAttribute_ImpliedType(t *testing.T) {
+	t.Parallel()
+
+	newAttribute := func(attrType cty.Type, nestedType *schema.NestedAttributeType) *schema.Attribute {
+		return &schema.Attribute{
+			Type:            attrType,
+			NestedType:      nestedType,
+			Required:        false,
+			Optional:        false,
+			Computed:        false,
+			Sensitive:       false,
+			Description:     "",
+			DescriptionKind: "",
+			Deprecated:      false,
+		}
+	}
+	nested := func(mode schema.NestingMode) *schema.Attribute {
+		return newAttribute(cty.NilType, &schema.NestedAttributeType{
+			NestingMode: mode,
+			Attributes: map[string]*schema.Attribute{
+				"required": {Type: cty.String, Required: true},
+				"optional": {Type: cty.Number, Optional: true},
+			},
+		})
+	}
+	objType := cty.ObjectWithOptionalAttrs(
+		map[string]cty.Type{"required": cty.String, "optional": cty.Number},
+		[]string{"optional"},
+	)
+
+	cases := map[string]struct {
+		attr     *schema.Attribute
+		expected cty.Type
+	}{
+		"Basic":  {attr: newAttribute(cty.List(cty.String), nil), expected: cty.List(cty.String)},
+		"Single": {attr: nested(schema.NestingSingle), expected: objType},
+		"List":   {attr: nested(schema.NestingList), expected: cty.List(objType)},
+		"Set":    {attr: nested(schema.NestingSet), expected: cty.Set(objType)},
+		"Map":    {attr: nested(schema.NestingMap), expected: cty.Map(objType)},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := tcase.attr.ImpliedType(); !actual.Equals(tcase.expected) {
+					t.Errorf("Case \"%s\": expected %#v, got %#v", tcname, tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
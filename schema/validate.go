@@ -0,0 +1,280 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+var (
+	// ErrUnknownSchema is returned when no schema exists for the validated block.
+	ErrUnknownSchema = errors.New("unknown schema")
+	// ErrUnknownAttribute is returned when an attribute is not defined by the schema.
+	ErrUnknownAttribute = errors.New("unsupported attribute")
+	// ErrUnknownBlock is returned when a nested block is not defined by the schema.
+	ErrUnknownBlock = errors.New("unsupported block type")
+	// ErrMissingAttribute is returned when a required attribute is not set.
+	ErrMissingAttribute = errors.New("missing required attribute")
+	// ErrComputedAttribute is returned when a computed-only attribute is set.
+	ErrComputedAttribute = errors.New("computed attribute can't be set")
+	// ErrTypeMismatch is returned when a literal value doesn't match the type of the attribute.
+	ErrTypeMismatch = errors.New("invalid attribute value")
+	// ErrInvalidNestedBlock is returned when nested blocks don't match the nesting mode of the schema
+	// (count of blocks or labels).
+	ErrInvalidNestedBlock = errors.New("invalid nested block")
+)
+
+//nolint:gochecknoglobals // Better to keep it as **internal** global var than define it each time
+var (
+	resourceMetaArguments = map[string]bool{"count": true, "for_each": true, "provider": true, "depends_on": true}
+	resourceMetaBlocks    = map[string]bool{"lifecycle": true, "provisioner": true, "connection": true}
+	dataMetaBlocks        = map[string]bool{"lifecycle": true}
+	providerMetaArguments = map[string]bool{"alias": true, "version": true}
+)
+
+// Validate validates a `resource`, `data` or `provider` block signature against the related schema:
+//
+// - attributes and nested blocks must be defined by the schema (meta-arguments like `count` are allowed)
+// - required attributes must be set and computed-only attributes must not be set
+// - literal values must match the type of the attribute (values containing expressions are not checked)
+// - nested blocks must match the nesting mode of the schema (count of blocks and labels)
+//
+// It returns all errors joined, prefixed by the path of the attribute or of the block
+// (e.g. `resource "aws_instance" "web" > instnace_type: unsupported attribute`).
+func (s *ProvidersSchema) Validate(sig *tfsig.BlockSignature) error {
+	header := sig.GetHeader()
+	labels := sig.GetLabels()
+
+	if len(labels) == 0 {
+		return fmt.Errorf("%s: %w", header, ErrUnknownSchema)
+	}
+
+	var (
+		schema     *Schema
+		exists     bool
+		metaArgs   map[string]bool
+		metaBlocks map[string]bool
+	)
+
+	switch sig.GetType() {
+	case "resource":
+		schema, exists = s.ResourceSchema(labels[0])
+		metaArgs, metaBlocks = resourceMetaArguments, resourceMetaBlocks
+	case "data":
+		schema, exists = s.DataSourceSchema(labels[0])
+		metaArgs, metaBlocks = resourceMetaArguments, dataMetaBlocks
+	case "provider":
+		schema, exists = s.ProviderConfigSchema(labels[0])
+		metaArgs, metaBlocks = providerMetaArguments, nil
+	}
+
+	if !exists || schema.Block == nil {
+		return fmt.Errorf("%s: %w", header, ErrUnknownSchema)
+	}
+
+	return errors.Join(validateBlock([]string{header}, sig, schema.Block, metaArgs, metaBlocks)...)
+}
+
+// ValidateFile validates each top-level `resource`, `data` and `provider` block of the file (see `Validate()`),
+// other blocks are ignored.
+func (s *ProvidersSchema) ValidateFile(file *tfsig.FileSignature) error {
+	errs := []error{}
+
+	for _, elem := range file.GetElements() {
+		if !elem.IsBodyBlock() {
+			continue
+		}
+
+		switch elem.GetBodyBlock().GetType() {
+		case "resource", "data", "provider":
+			if err := s.Validate(elem.GetBodyBlock()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+/** Private **/
+
+func validateBlock(
+	path []string,
+	sig *tfsig.BlockSignature,
+	block *Block,
+	metaArgs, metaBlocks map[string]bool,
+) []error {
+	errs := []error{}
+	setAttrs := map[string]bool{}
+	blockCounts := map[string]int{}
+	dynamicBlocks := map[string]bool{}
+
+	for _, elem := range sig.GetElements() {
+		switch {
+		case elem.IsBodyAttribute():
+			name := elem.GetName()
+			setAttrs[name] = true
+
+			if metaArgs[name] {
+				continue
+			}
+
+			if err := validateAttribute(block.Attributes[name], *elem.GetBodyAttribute()); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", joinPath(path, name), err))
+			}
+		case elem.IsBodyBlock():
+			if child := elem.GetBodyBlock(); !metaBlocks[child.GetType()] {
+				errs = append(errs, validateChildBlock(path, block, child, blockCounts, dynamicBlocks)...)
+			}
+		}
+	}
+
+	return append(errs, validateRequirements(path, block, setAttrs, blockCounts, dynamicBlocks)...)
+}
+
+// validateChildBlock validates a nested block against its schema and counts it (or flags it as dynamic).
+func validateChildBlock(
+	path []string,
+	block *Block,
+	child *tfsig.BlockSignature,
+	blockCounts map[string]int,
+	dynamicBlocks map[string]bool,
+) []error {
+	name, content, dynamic := dynamicContent(child)
+	childPath := append(append([]string{}, path...), child.GetHeader())
+
+	nested, exists := block.BlockTypes[name]
+	if !exists || nested.Block == nil {
+		return []error{fmt.Errorf("%s: %w", joinPath(path, child.GetHeader()), ErrUnknownBlock)}
+	}
+
+	errs := []error{}
+
+	if dynamic {
+		dynamicBlocks[name] = true
+	} else {
+		blockCounts[name]++
+
+		if err := validateLabels(nested, child); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", strings.Join(childPath, " > "), err))
+		}
+	}
+
+	if content != nil {
+		errs = append(errs, validateBlock(childPath, content, nested.Block, nil, nil)...)
+	}
+
+	return errs
+}
+
+// validateRequirements checks that required attributes are set and that nested blocks cardinality is respected
+// (dynamic blocks can't be counted).
+func validateRequirements(
+	path []string,
+	block *Block,
+	setAttrs map[string]bool,
+	blockCounts map[string]int,
+	dynamicBlocks map[string]bool,
+) []error {
+	errs := []error{}
+
+	for _, name := range slices.Sorted(maps.Keys(block.Attributes)) {
+		if block.Attributes[name].Required && !setAttrs[name] {
+			errs = append(errs, fmt.Errorf("%s: %w", joinPath(path, name), ErrMissingAttribute))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(block.BlockTypes)) {
+		if dynamicBlocks[name] {
+			continue
+		}
+
+		if err := validateCardinality(block.BlockTypes[name], blockCounts[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", joinPath(path, name), err))
+		}
+	}
+
+	return errs
+}
+
+func validateAttribute(attr *Attribute, value cty.Value) error {
+	if attr == nil {
+		return ErrUnknownAttribute
+	}
+
+	if attr.Computed && !attr.Optional && !attr.Required {
+		return ErrComputedAttribute
+	}
+
+	literal, _ := value.UnmarkDeep()
+	if literal.IsNull() || tokens.ContainsCapsule(&literal) {
+		// Expressions can't be checked without evaluating them
+		return nil
+	}
+
+	attrType := attr.ImpliedType()
+	if _, err := convert.Convert(literal, attrType); err != nil {
+		return fmt.Errorf("%w: expected %s: %w", ErrTypeMismatch, typeexpr.TypeString(attrType), err)
+	}
+
+	return nil
+}
+
+func validateLabels(nested *NestedBlock, child *tfsig.BlockSignature) error {
+	expected := 0
+	if nested.NestingMode == NestingMap {
+		expected = 1
+	}
+
+	if count := len(child.GetLabels()); count != expected {
+		return fmt.Errorf("%w: expected %d label(s) for %s nesting mode, got %d", ErrInvalidNestedBlock, expected,
+			nested.NestingMode, count)
+	}
+
+	return nil
+}
+
+func validateCardinality(nested *NestedBlock, count int) error {
+	maxItems := nested.MaxItems
+	if nested.NestingMode == NestingSingle || nested.NestingMode == NestingGroup {
+		maxItems = 1
+	}
+
+	switch {
+	case count < nested.MinItems:
+		return fmt.Errorf("%w: at least %d block(s) required, got %d", ErrInvalidNestedBlock, nested.MinItems, count)
+	case maxItems > 0 && count > maxItems:
+		return fmt.Errorf("%w: at most %d block(s) allowed, got %d", ErrInvalidNestedBlock, maxItems, count)
+	}
+
+	return nil
+}
+
+// dynamicContent returns the name of the generated block type, its content (`content` block of `dynamic` blocks)
+// and whether the block is a `dynamic` block.
+func dynamicContent(sig *tfsig.BlockSignature) (string, *tfsig.BlockSignature, bool) {
+	if sig.GetType() != "dynamic" || len(sig.GetLabels()) != 1 {
+		return sig.GetType(), sig, false
+	}
+
+	for _, elem := range sig.GetElements() {
+		if elem.IsBodyBlock() && elem.GetBodyBlock().GetType() == "content" {
+			return sig.GetLabels()[0], elem.GetBodyBlock(), true
+		}
+	}
+
+	return sig.GetLabels()[0], nil, true
+}
+
+func joinPath(path []string, name string) string {
+	return strings.Join(append(append([]string{}, path...), name), " > ")
+}
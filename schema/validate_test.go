@@ -0,0 +1,269 @@
+package schema_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/schema"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func assertValidationError(t *testing.T, err error, expected []error, message string) {
+	t.Helper()
+
+	if message == "" {
+		if err != nil {
+			t.Errorf("Case \"%s\": unexpected error %v", t.Name(), err)
+		}
+
+		return
+	}
+
+	for _, expectedErr := range expected {
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), expectedErr, err)
+		}
+	}
+
+	if err == nil || err.Error() != message {
+		t.Errorf("Case \"%s\": expected\n%s\ngot\n%v", t.Name(), message, err)
+	}
+}
+
+func TestProvidersSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	doc, err := schema.Load("testdata/providers.schema.json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	newInstance := func() *tfsig.BlockSignature {
+		sig := tfsig.NewResource("aws_instance", "web")
+		sig.AppendAttribute("ami", cty.StringVal("ami-123"))
+
+		return sig
+	}
+	newListener := func() *tfsig.BlockSignature {
+		sig := tfsig.NewResource("aws_lb_listener", "front")
+		sig.AppendAttribute("port", cty.NumberIntVal(443))
+
+		action := tfsig.NewSignature("default_action")
+		action.AppendAttribute("type", cty.StringVal("forward"))
+		sig.AppendChild(action)
+
+		return sig
+	}
+
+	cases := map[string]struct {
+		value    func() *tfsig.BlockSignature
+		expected []error
+		message  string
+	}{
+		"Valid resource": {
+			value: func() *tfsig.BlockSignature {
+				sig := newInstance()
+				sig.AppendAttribute("count", cty.NumberIntVal(2))
+				sig.AppendAttribute("instance_type", *tokens.NewIdentValue("var.instance_type"))
+				sig.AppendAttribute("monitoring", cty.StringVal("true"))
+				sig.AppendAttribute("tags", cty.ObjectVal(map[string]cty.Value{"Name": cty.StringVal("web")}))
+				sig.AppendAttribute("metadata_options", cty.ObjectVal(map[string]cty.Value{
+					"http_tokens": cty.StringVal("required"),
+				}))
+				sig.AppendChild(tfsig.NewSignature("root_block_device"))
+
+				for _, name := range []string{"/dev/sdb", "/dev/sdc"} {
+					device := tfsig.NewSignature("ebs_block_device")
+					device.AppendAttribute("device_name", cty.StringVal(name))
+					sig.AppendChild(device)
+				}
+
+				sig.Lifecycle(tfsig.LifecycleConfig{
+					CreateBeforeDestroy: nil,
+					PreventDestroy:      nil,
+					IgnoreChanges:       []string{"tags"},
+					ReplaceTriggeredBy:  nil,
+					Precondition:        nil,
+					Postcondition:       nil,
+				})
+
+				return sig
+			},
+			expected: nil,
+			message:  "",
+		},
+		"Valid data source": {
+			value: func() *tfsig.BlockSignature {
+				sig := tfsig.NewSignature("data", "aws_ami", "ubuntu")
+				sig.AppendAttribute("owners", cty.TupleVal([]cty.Value{cty.StringVal("self")}))
+
+				return sig
+			},
+			expected: nil,
+			message:  "",
+		},
+		"Valid provider": {
+			value: func() *tfsig.BlockSignature {
+				sig := tfsig.NewSignature("provider", "aws")
+				sig.AppendAttribute("alias", cty.StringVal("eu"))
+				sig.AppendAttribute("region", cty.StringVal("eu-west-1"))
+
+				return sig
+			},
+			expected: nil,
+			message:  "",
+		},
+		"Unknown schema": {
+			value: func() *tfsig.BlockSignature {
+				return tfsig.NewResource("aws_unknown", "web")
+			},
+			expected: []error{schema.ErrUnknownSchema},
+			message:  `resource "aws_unknown" "web": unknown schema`,
+		},
+		"Unknown attribute and block": {
+			value: func() *tfsig.BlockSignature {
+				sig := newInstance()
+				sig.AppendAttribute("instnace_type", cty.StringVal("t3.micro"))
+				sig.AppendChild(tfsig.NewSignature("root_device"))
+
+				return sig
+			},
+			expected: []error{schema.ErrUnknownAttribute, schema.ErrUnknownBlock},
+			message: `resource "aws_instance" "web" > instnace_type: unsupported attribute
+resource "aws_instance" "web" > root_device: unsupported block type`,
+		},
+		"Missing and computed attributes": {
+			value: func() *tfsig.BlockSignature {
+				sig := tfsig.NewResource("aws_instance", "web")
+				sig.AppendAttribute("id", cty.StringVal("i-123"))
+				sig.AppendAttribute("arn", cty.StringVal("arn"))
+
+				return sig
+			},
+			expected: []error{schema.ErrComputedAttribute, schema.ErrMissingAttribute},
+			message: `resource "aws_instance" "web" > arn: computed attribute can't be set
+resource "aws_instance" "web" > ami: missing required attribute`,
+		},
+		"Type mismatch": {
+			value: func() *tfsig.BlockSignature {
+				sig := newInstance()
+				sig.AppendAttribute("monitoring", cty.StringVal("yes"))
+				sig.AppendAttribute("metadata_options", cty.ObjectVal(map[string]cty.Value{
+					"http_put_response_hop_limit": cty.NumberIntVal(1),
+				}))
+
+				device := tfsig.NewSignature("root_block_device")
+				device.AppendAttribute("volume_size", cty.StringVal("big"))
+				sig.AppendChild(device)
+
+				return sig
+			},
+			expected: []error{schema.ErrTypeMismatch},
+			message: `resource "aws_instance" "web" > monitoring: invalid attribute value: expected bool: ` +
+				`a bool is required
+resource "aws_instance" "web" > metadata_options: invalid attribute value: ` +
+				`expected object({http_put_response_hop_limit=number,http_tokens=string}): ` +
+				`attribute "http_tokens" is required
+resource "aws_instance" "web" > root_block_device > volume_size: invalid attribute value: expected number: ` +
+				`a number is required`,
+		},
+		"Nesting mode cardinality": {
+			value: func() *tfsig.BlockSignature {
+				sig := newInstance()
+				sig.AppendChild(tfsig.NewSignature("root_block_device"))
+				sig.AppendChild(tfsig.NewSignature("root_block_device"))
+				sig.AppendChild(tfsig.NewSignature("credit_specification"))
+				sig.AppendChild(tfsig.NewSignature("credit_specification"))
+
+				return sig
+			},
+			expected: []error{schema.ErrInvalidNestedBlock},
+			message: `resource "aws_instance" "web" > credit_specification: invalid nested block: ` +
+				`at most 1 block(s) allowed, got 2
+resource "aws_instance" "web" > root_block_device: invalid nested block: at most 1 block(s) allowed, got 2`,
+		},
+		"Missing nested block": {
+			value: func() *tfsig.BlockSignature {
+				sig := tfsig.NewResource("aws_lb_listener", "front")
+				sig.AppendAttribute("port", cty.NumberIntVal(443))
+
+				return sig
+			},
+			expected: []error{schema.ErrInvalidNestedBlock},
+			message: `resource "aws_lb_listener" "front" > default_action: invalid nested block: ` +
+				`at least 1 block(s) required, got 0`,
+		},
+		"Nesting mode labels": {
+			value: func() *tfsig.BlockSignature {
+				sig := newListener()
+				sig.AppendChild(tfsig.NewSignature("header"))
+				sig.AppendChild(tfsig.NewSignature("default_action", "label"))
+
+				return sig
+			},
+			expected: []error{schema.ErrInvalidNestedBlock, schema.ErrMissingAttribute},
+			message: `resource "aws_lb_listener" "front" > header: invalid nested block: ` +
+				`expected 1 label(s) for map nesting mode, got 0
+resource "aws_lb_listener" "front" > header > value: missing required attribute
+resource "aws_lb_listener" "front" > default_action "label": invalid nested block: ` +
+				`expected 0 label(s) for list nesting mode, got 1
+resource "aws_lb_listener" "front" > default_action "label" > type: missing required attribute`,
+		},
+		"Dynamic block": {
+			value: func() *tfsig.BlockSignature {
+				sig := tfsig.NewResource("aws_lb_listener", "front")
+				sig.AppendAttribute("port", cty.NumberIntVal(443))
+
+				content := tfsig.NewSignature("content")
+				content.AppendAttribute("typo", *tokens.NewIdentValue("default_action.value"))
+
+				dynamic := tfsig.NewSignature("dynamic", "default_action")
+				dynamic.AppendAttribute("for_each", *tokens.NewIdentValue("var.actions"))
+				dynamic.AppendChild(content)
+				sig.AppendChild(dynamic)
+
+				return sig
+			},
+			expected: []error{schema.ErrUnknownAttribute, schema.ErrMissingAttribute},
+			message: `resource "aws_lb_listener" "front" > dynamic "default_action" > typo: unsupported attribute
+resource "aws_lb_listener" "front" > dynamic "default_action" > type: missing required attribute`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				assertValidationError(t, doc.Validate(tcase.value()), tcase.expected, tcase.message)
+			},
+		)
+	}
+}
+
+func TestProvidersSchema_ValidateFile(t *testing.T) {
+	t.Parallel()
+
+	doc, err := schema.Load("testdata/providers.schema.json")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	variable := tfsig.NewSignature("variable", "name")
+	variable.AppendAttribute("unknown", cty.True)
+
+	instance := tfsig.NewResource("aws_instance", "web")
+	instance.AppendAttribute("ami", cty.StringVal("ami-123"))
+	instance.AppendAttribute("instnace_type", cty.StringVal("t3.micro"))
+
+	err = doc.ValidateFile(tfsig.NewFileSignature(variable, instance, tfsig.NewSignature("provider", "aws")))
+
+	expected := `resource "aws_instance" "web" > instnace_type: unsupported attribute`
+	if !errors.Is(err, schema.ErrUnknownAttribute) || err.Error() != expected {
+		t.Errorf("expected\n%s\ngot\n%v", expected, err)
+	}
+}
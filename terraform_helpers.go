@@ -2,6 +2,10 @@ package tfsig
 
 import (
 	"regexp"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
 )
 
 var (
@@ -23,3 +27,16 @@ func ToTerraformIdentifier(s string) string {
 	// Identifier must start with a letter or underscore !
 	return invalidFirstCharMatcher.ReplaceAllString(id, "_")
 }
+
+// Sensitive is the cty mark for sensitive values (e.g. `cty.StringVal("secret").Mark(tfsig.Sensitive)`)
+//
+// Marked values are rendered as is by `Build()` methods and as `"(sensitive)"` placeholders
+// by `BuildRedacted()` methods.
+const Sensitive = tokens.SensitiveMark
+
+// Null returns a null value, rendered as the `null` keyword
+//
+// E.g. it can be used to explicitly unset a module input having a default value.
+func Null() cty.Value {
+	return cty.NullVal(cty.DynamicPseudoType)
+}
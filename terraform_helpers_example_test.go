@@ -3,6 +3,9 @@ package tfsig_test
 import (
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/yoanm/go-tfsig"
 )
 
@@ -18,3 +21,16 @@ func ExampleToTerraformIdentifier() {
 	// an.identifier becomes an-identifier
 	// 0id becomes _id
 }
+
+func ExampleNull() {
+	sig := tfsig.NewSignature("module", "vpc")
+	sig.AppendAttribute("source", cty.StringVal("./vpc"))
+	sig.AppendAttribute("cidr_block", tfsig.Null())
+
+	fmt.Println(string(hclwrite.Format(sig.BuildTokens().Bytes())))
+	// Output:
+	// module "vpc" {
+	//   source     = "./vpc"
+	//   cidr_block = null
+	// }
+}
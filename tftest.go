@@ -0,0 +1,169 @@
+package tfsig
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// TestFileExtension is the extension of terraform test files.
+const TestFileExtension = ".tftest.hcl"
+
+// RunCommand is the command executed by a terraform test `run` block.
+type RunCommand string
+
+const (
+	// PlanCommand only plans the changes for the `run` block.
+	PlanCommand RunCommand = "plan"
+	// ApplyCommand plans and applies the changes for the `run` block (terraform default).
+	ApplyCommand RunCommand = "apply"
+)
+
+// Assertion is used as Asserts property of RunConfig and by `NewCheck()`
+// It's basically a wrapper for terraform `assert` blocks, Condition is rendered as an expression.
+type Assertion struct {
+	Condition    string
+	ErrorMessage string
+}
+
+// RunModule is used as Module property of RunConfig
+// It's basically a wrapper for terraform test `module` block, Version is ignored if empty.
+type RunModule struct {
+	Source  string
+	Version string
+}
+
+// RunConfig is used as argument for `NewRun()` function
+// It's basically a wrapper for terraform test `run` block.
+type RunConfig struct {
+	// Command is not rendered if empty
+	Command RunCommand
+	// Variables are rendered into a `variables` block (keys are sorted)
+	Variables map[string]cty.Value
+	// Module is not rendered if nil
+	Module *RunModule
+	// Providers are rendered as a `providers` map, keys and values are rendered as 'ident' tokens
+	// (e.g. `{"aws": "aws.alternate"}`)
+	Providers map[string]string
+	// ExpectFailures are rendered as 'ident' tokens (e.g. `var.name` or `aws_s3_bucket.this`)
+	ExpectFailures []string
+	// Asserts are rendered as `assert` blocks, in the provided order
+	Asserts []Assertion
+}
+
+// NewRun returns a BlockSignature pointer for a terraform test `run` block with provided name and configuration.
+func NewRun(name string, config RunConfig) *BlockSignature {
+	sig := NewSignature("run", name)
+
+	if config.Command != "" {
+		sig.AppendAttribute("command", *tokens.NewIdentValue(string(config.Command)))
+	}
+
+	if config.Providers != nil {
+		providers := make([]tokens.ObjectEntry, 0, len(config.Providers))
+		for _, key := range slices.Sorted(maps.Keys(config.Providers)) {
+			providers = append(providers, tokens.NewObjectEntry(key, *tokens.NewIdentValue(config.Providers[key])))
+		}
+
+		sig.AppendAttribute("providers", tokens.NewOrderedObjectValue(providers...))
+	}
+
+	if config.ExpectFailures != nil {
+		sig.AppendAttribute("expect_failures", *tokens.NewIdentListValue(config.ExpectFailures))
+	}
+
+	if config.Variables != nil {
+		appendSeparatedChild(sig, NewVariables(config.Variables))
+	}
+
+	if config.Module != nil {
+		moduleSig := NewSignature("module")
+		moduleSig.AppendAttribute("source", cty.StringVal(config.Module.Source))
+
+		if config.Module.Version != "" {
+			moduleSig.AppendAttribute("version", cty.StringVal(config.Module.Version))
+		}
+
+		appendSeparatedChild(sig, moduleSig)
+	}
+
+	for _, assertion := range config.Asserts {
+		appendSeparatedChild(sig, newAssertionBlock(assertion))
+	}
+
+	return sig
+}
+
+// NewVariables returns a BlockSignature pointer for a terraform test `variables` block, keys are sorted
+//
+// It can be used either at the top level of a test file or inside a `run` block.
+func NewVariables(values map[string]cty.Value) *BlockSignature {
+	sig := NewSignature("variables")
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		sig.AppendAttribute(name, values[name])
+	}
+
+	return sig
+}
+
+// NewProvider returns a BlockSignature pointer for a `provider` block, alias is not rendered if empty
+//
+// Inside a test file, it overrides the provider configuration for all `run` blocks.
+func NewProvider(name, alias string) *BlockSignature {
+	sig := NewSignature("provider", name)
+	appendAliasAttribute(sig, alias)
+
+	return sig
+}
+
+// NewMockProvider returns a BlockSignature pointer for a terraform test `mock_provider` block,
+// alias is not rendered if empty.
+func NewMockProvider(name, alias string) *BlockSignature {
+	sig := NewSignature("mock_provider", name)
+	appendAliasAttribute(sig, alias)
+
+	return sig
+}
+
+// NewOverrideResource returns a BlockSignature pointer for a terraform test `override_resource` block
+//
+// Target is rendered as 'ident' token (e.g. `aws_s3_bucket.this`), values are not rendered if nil.
+func NewOverrideResource(target string, values *cty.Value) *BlockSignature {
+	return newOverrideBlock("override_resource", target, "values", values)
+}
+
+// NewOverrideData returns a BlockSignature pointer for a terraform test `override_data` block
+// (see `NewOverrideResource()`).
+func NewOverrideData(target string, values *cty.Value) *BlockSignature {
+	return newOverrideBlock("override_data", target, "values", values)
+}
+
+// NewOverrideModule returns a BlockSignature pointer for a terraform test `override_module` block
+// (see `NewOverrideResource()`), outputs are not rendered if nil.
+func NewOverrideModule(target string, outputs *cty.Value) *BlockSignature {
+	return newOverrideBlock("override_module", target, "outputs", outputs)
+}
+
+/** Private **/
+
+func newAssertionBlock(assertion Assertion) *BlockSignature {
+	return newConditionBlock(assertBlockType, *tokens.NewExpressionValue(assertion.Condition), assertion.ErrorMessage)
+}
+
+func appendAliasAttribute(sig *BlockSignature, alias string) {
+	if alias != "" {
+		sig.AppendAttribute("alias", cty.StringVal(alias))
+	}
+}
+
+func newOverrideBlock(blockType, target, valuesAttr string, values *cty.Value) *BlockSignature {
+	sig := NewSignature(blockType)
+	sig.AppendAttribute("target", *tokens.NewIdentValue(target))
+	AppendAttributeIfNotNil(sig, valuesAttr, values)
+
+	return sig
+}
@@ -0,0 +1,120 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func ExampleNewRun() {
+	file := tfsig.NewFileSignature(
+		tfsig.NewVariables(map[string]cty.Value{"bucket_prefix": cty.StringVal("test")}),
+		tfsig.NewProvider("aws", "alternate"),
+		tfsig.NewRun(
+			"valid_name",
+			tfsig.RunConfig{
+				Command:   tfsig.PlanCommand,
+				Variables: map[string]cty.Value{"name": cty.StringVal("my-bucket")},
+				Providers: map[string]string{"aws": "aws.alternate"},
+				Asserts: []tfsig.Assertion{
+					{
+						Condition:    `aws_s3_bucket.this.bucket=="test-my-bucket"`,
+						ErrorMessage: "Invalid bucket name",
+					},
+					{
+						Condition:    "length(aws_s3_bucket.this.tags)>0",
+						ErrorMessage: "Tags are required",
+					},
+				},
+			},
+		),
+		tfsig.NewRun(
+			"invalid_name",
+			tfsig.RunConfig{
+				Variables:      map[string]cty.Value{"name": cty.StringVal("")},
+				Module:         &tfsig.RunModule{Source: "./tests/setup", Version: ""},
+				ExpectFailures: []string{"var.name"},
+			},
+		),
+	)
+
+	fmt.Print(string(file.Bytes()))
+	// Output:
+	// variables {
+	//   bucket_prefix = "test"
+	// }
+	//
+	// provider "aws" {
+	//   alias = "alternate"
+	// }
+	//
+	// run "valid_name" {
+	//   command = plan
+	//   providers = {
+	//     aws = aws.alternate
+	//   }
+	//
+	//   variables {
+	//     name = "my-bucket"
+	//   }
+	//
+	//   assert {
+	//     condition     = aws_s3_bucket.this.bucket == "test-my-bucket"
+	//     error_message = "Invalid bucket name"
+	//   }
+	//
+	//   assert {
+	//     condition     = length(aws_s3_bucket.this.tags) > 0
+	//     error_message = "Tags are required"
+	//   }
+	// }
+	//
+	// run "invalid_name" {
+	//   expect_failures = [var.name]
+	//
+	//   variables {
+	//     name = ""
+	//   }
+	//
+	//   module {
+	//     source = "./tests/setup"
+	//   }
+	// }
+}
+
+func ExampleNewMockProvider() {
+	values := cty.ObjectVal(map[string]cty.Value{"arn": cty.StringVal("arn:aws:s3:::bucket")})
+	outputs := cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("vpc-123")})
+
+	file := tfsig.NewFileSignature(
+		tfsig.NewMockProvider("aws", ""),
+		tfsig.NewOverrideResource("aws_s3_bucket.this", &values),
+		tfsig.NewOverrideData("data.aws_caller_identity.current", nil),
+		tfsig.NewOverrideModule("module.vpc", &outputs),
+	)
+
+	fmt.Print(string(file.Bytes()))
+	// Output:
+	// mock_provider "aws" {
+	// }
+	//
+	// override_resource {
+	//   target = aws_s3_bucket.this
+	//   values = {
+	//     arn = "arn:aws:s3:::bucket"
+	//   }
+	// }
+	//
+	// override_data {
+	//   target = data.aws_caller_identity.current
+	// }
+	//
+	// override_module {
+	//   target = module.vpc
+	//   outputs = {
+	//     id = "vpc-123"
+	//   }
+	// }
+}
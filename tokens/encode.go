@@ -0,0 +1,38 @@
+package tokens
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// JSONEncodeFunctionName is the name of the terraform function encoding a value to a JSON string.
+	JSONEncodeFunctionName = "jsonencode"
+	// YAMLEncodeFunctionName is the name of the terraform function encoding a value to a YAML string.
+	YAMLEncodeFunctionName = "yamlencode"
+)
+
+// JSONEncode takes a `cty.Value` and wraps it into a `jsonencode(...)` function call
+//
+// The value is rendered as an HCL literal (objects are rendered multi-line), special `cty.Value` capsules
+// are de-encapsulated at any depth. Returned value is itself a special `cty.Value` capsule.
+func JSONEncode(val cty.Value) cty.Value {
+	return NewFunctionCallValue(JSONEncodeFunctionName, val)
+}
+
+// YAMLEncode takes a `cty.Value` and wraps it into a `yamlencode(...)` function call (see `JSONEncode()`).
+func YAMLEncode(val cty.Value) cty.Value {
+	return NewFunctionCallValue(YAMLEncodeFunctionName, val)
+}
+
+// NewFunctionCallValue creates a special `cty.Value` capsule holding a call to the provided function,
+// each argument is converted with `Generate()`.
+func NewFunctionCallValue(name string, args ...cty.Value) cty.Value {
+	argTokens := make([]hclwrite.Tokens, len(args))
+
+	for idx := range args {
+		argTokens[idx] = Generate(&args[idx])
+	}
+
+	return ToValue(hclwrite.TokensForFunctionCall(name, argTokens...))
+}
@@ -0,0 +1,68 @@
+package tokens_test
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func ExampleJSONEncode() {
+	policy := cty.ObjectVal(map[string]cty.Value{
+		"Version": cty.StringVal("2012-10-17"),
+		"Statement": cty.TupleVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"Effect":   cty.StringVal("Allow"),
+				"Action":   cty.ListVal([]cty.Value{cty.StringVal("s3:GetObject")}),
+				"Resource": *tokens.NewExpressionValue("\"${aws_s3_bucket.this.arn}/*\""),
+			}),
+		}),
+	})
+
+	hclFile := hclwrite.NewEmptyFile()
+	value := tokens.JSONEncode(policy)
+	hclFile.Body().SetAttributeRaw("policy", tokens.Generate(&value))
+
+	fmt.Println(string(hclFile.Bytes()))
+	// Output:
+	// policy = jsonencode({
+	//   "Statement" = [{
+	//     "Action"   = ["s3:GetObject"]
+	//     "Effect"   = "Allow"
+	//     "Resource" = "${aws_s3_bucket.this.arn}/*"
+	//   }]
+	//   "Version" = "2012-10-17"
+	// })
+}
+
+func ExampleYAMLEncode() {
+	manifest := cty.ObjectVal(map[string]cty.Value{
+		"apiVersion": cty.StringVal("v1"),
+		"kind":       cty.StringVal("ConfigMap"),
+		"metadata": cty.ObjectVal(map[string]cty.Value{
+			"name": *tokens.NewIdentValue("var.name"),
+			"labels": cty.MapVal(map[string]cty.Value{
+				"app.kubernetes.io/name": cty.StringVal("app"),
+			}),
+		}),
+	})
+
+	hclFile := hclwrite.NewEmptyFile()
+	value := tokens.YAMLEncode(manifest)
+	hclFile.Body().SetAttributeRaw("manifest", tokens.Generate(&value))
+
+	fmt.Println(string(hclFile.Bytes()))
+	// Output:
+	// manifest = yamlencode({
+	//   "apiVersion" = "v1"
+	//   "kind"       = "ConfigMap"
+	//   "metadata" = {
+	//     "labels" = {
+	//       "app.kubernetes.io/name" = "app"
+	//     }
+	//     "name" = var.name
+	//   }
+	// })
+}
@@ -0,0 +1,51 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestNewFunctionCallValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    cty.Value
+		expected string
+	}{
+		"No argument": {tokens.NewFunctionCallValue("timestamp"), "timestamp()"},
+		"Several arguments": {
+			tokens.NewFunctionCallValue("merge", *tokens.NewIdentValue("local.tags"), cty.EmptyObjectVal),
+			"merge(local.tags, {})",
+		},
+		"Nested call": {
+			tokens.NewFunctionCallValue("base64encode", tokens.JSONEncode(cty.StringVal("a"))),
+			`base64encode(jsonencode("a"))`,
+		},
+		"List of capsules": {
+			tokens.YAMLEncode(*tokens.NewIdentListValue([]string{"var.a", "var.b"})),
+			"yamlencode([var.a, var.b])",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if !tokens.IsCapsuleType(tcase.value.Type()) {
+					t.Fatalf("Case \"%s\": expected a capsule, got %s", t.Name(), tcase.value.Type().FriendlyName())
+				}
+
+				actual := string(hclwrite.Format(tokens.Generate(&tcase.value).Bytes()))
+				if actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected %q, got %q", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
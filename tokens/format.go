@@ -0,0 +1,232 @@
+package tokens
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const indentSize = 2
+
+// GenerateOption is a functional option used to configure how lists, sets and tuples are rendered
+// by `Generate()`, `GenerateFromIterable()` and `MergeIterableAndGenerate()`
+//
+// By default, those collections are rendered on a single line.
+// When an option triggers the multi-line format, each element is rendered on its own line with a trailing comma.
+type GenerateOption func(c *generateConfig)
+
+// WithMultiLine renders all non-empty lists, sets and tuples with one element per line.
+func WithMultiLine() GenerateOption {
+	return func(c *generateConfig) {
+		c.multiLine = true
+	}
+}
+
+// WithMaxElements renders lists, sets and tuples with more than the provided count of elements
+// with one element per line (0 means no limit).
+func WithMaxElements(count int) GenerateOption {
+	return func(c *generateConfig) {
+		c.maxElements = count
+	}
+}
+
+// WithMaxLineWidth renders lists, sets and tuples with one element per line if one of the rendered lines would be
+// longer than the provided width, indentation included (0 means no limit). See also `WithIndent()`.
+func WithMaxLineWidth(width int) GenerateOption {
+	return func(c *generateConfig) {
+		c.maxLineWidth = width
+	}
+}
+
+// WithIndent provides the position of the value in the final document, used to compute lines width:
+// `lineIndent` is the indentation of the line the value starts on and `column` is the column the value starts at
+//
+// E.g. for `  attr = [...]`, `lineIndent` is 2 and `column` is 9.
+func WithIndent(lineIndent, column int) GenerateOption {
+	return func(c *generateConfig) {
+		c.lineIndent = lineIndent
+		c.column = column
+	}
+}
+
+// Format renders the provided value with the provided options and encapsulates the result into
+// a special `cty.Value` capsule, in order to use a specific format for a single value.
+func Format(value cty.Value, opts ...GenerateOption) cty.Value {
+	return ToValue(Generate(&value, opts...))
+}
+
+/** Private **/
+
+type generateConfig struct {
+	multiLine    bool
+	maxElements  int
+	maxLineWidth int
+	lineIndent   int
+	column       int
+	redact       bool
+}
+
+func newGenerateConfig(opts []GenerateOption) generateConfig {
+	config := generateConfig{
+		multiLine:    false,
+		maxElements:  0,
+		maxLineWidth: 0,
+		lineIndent:   0,
+		column:       0,
+		redact:       false,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
+// isDefault returns true if lists are always rendered on a single line.
+func (c generateConfig) isDefault() bool {
+	return !c.multiLine && c.maxElements <= 0 && c.maxLineWidth <= 0
+}
+
+// nested returns the configuration for a value starting at the provided column of a line indented one more time.
+func (c generateConfig) nested(column int) generateConfig {
+	child := c
+	child.lineIndent = c.lineIndent + indentSize
+	child.column = column
+
+	return child
+}
+
+// generateFormatted converts a `cty.Value` to `hclwrite.Tokens` by recursively applying the configuration
+// to each list, set and tuple (and to each marked value).
+func generateFormatted(markedValue cty.Value, config generateConfig) hclwrite.Tokens {
+	value, marks := markedValue.Unmark()
+	if config.redact && hasMark(marks, SensitiveMark) {
+		return newRedactedTokens()
+	}
+
+	valType := value.Type()
+
+	if valType == cty.NilType || value.IsNull() || !value.IsKnown() || IsCapsuleType(valType) {
+		return Generate(&value)
+	}
+
+	switch {
+	case IsOrderedObjectType(valType):
+		return generateOrderedObject(FromOrderedObjectValue(value), config)
+	case isListLikeType(valType):
+		return generateFormattedList(value, config)
+	case isMapLikeType(valType):
+		return generateFormattedObject(value, config)
+	}
+
+	return Generate(&value)
+}
+
+func generateFormattedList(value cty.Value, config generateConfig) hclwrite.Tokens {
+	elements := make([]hclwrite.Tokens, 0, value.LengthInt())
+	childConfig := config.nested(config.lineIndent + indentSize)
+
+	for iter := value.ElementIterator(); iter.Next(); {
+		_, eVal := iter.Element()
+		elements = append(elements, generateFormatted(eVal, childConfig))
+	}
+
+	return config.buildList(elements)
+}
+
+func generateFormattedObject(value cty.Value, config generateConfig) hclwrite.Tokens {
+	elements := make([]hclwrite.Tokens, 0, value.LengthInt())
+
+	for iter := value.ElementIterator(); iter.Next(); {
+		eKey, eVal := iter.Element()
+		keyTokens := NewObjectKeyTokens(eKey.AsString())
+		childConfig := config.nested(config.lineIndent + indentSize + len(keyTokens.Bytes()) + len(" = "))
+		elements = append(
+			elements,
+			generateFormatted(eVal, childConfig).BuildTokens(NewEqualTokens().BuildTokens(keyTokens)),
+		)
+	}
+
+	return GenerateFromIterable(elements, cty.EmptyObject)
+}
+
+// buildList renders provided elements as a list, on a single line or one element per line based on the configuration.
+func (c generateConfig) buildList(elements []hclwrite.Tokens) hclwrite.Tokens {
+	singleLine := MergeIterableAndGenerate(cty.EmptyTupleVal, elements)
+	if len(elements) == 0 || !c.requiresMultiLine(len(elements), singleLine) {
+		return singleLine
+	}
+
+	newTokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenOBrack, Bytes: []byte{'['}, SpacesBefore: 0},
+		NewLineToken(),
+	}
+
+	for _, elem := range elements {
+		newTokens = append(newTokens, elem...)
+		newTokens = append(newTokens, NewCommaToken(), NewLineToken())
+	}
+
+	return append(newTokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte{']'}, SpacesBefore: 0})
+}
+
+func (c generateConfig) requiresMultiLine(count int, singleLine hclwrite.Tokens) bool {
+	if c.multiLine || (c.maxElements > 0 && count > c.maxElements) {
+		return true
+	}
+
+	if c.maxLineWidth <= 0 {
+		return false
+	}
+
+	for idx, line := range strings.Split(string(hclwrite.Format(singleLine.Bytes())), "\n") {
+		offset := c.lineIndent
+		if idx == 0 {
+			offset = c.column
+		}
+
+		if offset+len(line) > c.maxLineWidth {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitListElements splits tokens of list elements separated by commas (see `SplitIterable()`).
+func splitListElements(elems hclwrite.Tokens) []hclwrite.Tokens {
+	elements := []hclwrite.Tokens{}
+	current := hclwrite.Tokens{}
+	depth := 0
+
+	for _, token := range elems {
+		switch token.Type { //nolint:exhaustive // Other tokens are part of the current element
+		case hclsyntax.TokenOBrack, hclsyntax.TokenOBrace, hclsyntax.TokenOParen, hclsyntax.TokenTemplateInterp,
+			hclsyntax.TokenTemplateControl:
+			depth++
+		case hclsyntax.TokenCBrack, hclsyntax.TokenCBrace, hclsyntax.TokenCParen, hclsyntax.TokenTemplateSeqEnd:
+			depth--
+		case hclsyntax.TokenComma:
+			if depth == 0 {
+				elements = append(elements, current)
+				current = hclwrite.Tokens{}
+
+				continue
+			}
+		case hclsyntax.TokenNewline:
+			if depth == 0 {
+				continue
+			}
+		}
+
+		current = append(current, token)
+	}
+
+	if len(current) > 0 {
+		elements = append(elements, current)
+	}
+
+	return elements
+}
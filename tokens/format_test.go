@@ -0,0 +1,107 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestGenerate_withOptions(t *testing.T) {
+	t.Parallel()
+
+	strList := cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})
+	capsuleTuple := cty.TupleVal([]cty.Value{*tokens.NewIdentValue("var.a"), cty.StringVal("b")})
+
+	cases := map[string]struct {
+		value    cty.Value
+		opts     []tokens.GenerateOption
+		expected string
+	}{
+		"No option": {strList, nil, `["a", "b"]`},
+		"Multi-line": {
+			strList,
+			[]tokens.GenerateOption{tokens.WithMultiLine()},
+			"[\n  \"a\",\n  \"b\",\n]",
+		},
+		"Multi-line with capsule": {
+			capsuleTuple,
+			[]tokens.GenerateOption{tokens.WithMultiLine()},
+			"[\n  var.a,\n  \"b\",\n]",
+		},
+		"Empty list": {cty.ListValEmpty(cty.String), []tokens.GenerateOption{tokens.WithMultiLine()}, "[]"},
+		"Null list":  {cty.NullVal(cty.List(cty.String)), []tokens.GenerateOption{tokens.WithMultiLine()}, "null"},
+		"Under max elements": {
+			strList,
+			[]tokens.GenerateOption{tokens.WithMaxElements(2)},
+			`["a", "b"]`,
+		},
+		"Over max elements": {
+			capsuleTuple,
+			[]tokens.GenerateOption{tokens.WithMaxElements(1)},
+			"[\n  var.a,\n  \"b\",\n]",
+		},
+		"Under max width": {
+			strList,
+			[]tokens.GenerateOption{tokens.WithMaxLineWidth(10)},
+			`["a", "b"]`,
+		},
+		"Over max width": {
+			strList,
+			[]tokens.GenerateOption{tokens.WithMaxLineWidth(9)},
+			"[\n  \"a\",\n  \"b\",\n]",
+		},
+		"Over max width with indent": {
+			strList,
+			[]tokens.GenerateOption{tokens.WithMaxLineWidth(10), tokens.WithIndent(0, 1)},
+			"[\n  \"a\",\n  \"b\",\n]",
+		},
+		"Nested list": {
+			cty.TupleVal([]cty.Value{strList, cty.ListVal([]cty.Value{cty.StringVal("c")})}),
+			[]tokens.GenerateOption{tokens.WithMaxElements(1)},
+			"[\n  [\n    \"a\",\n    \"b\",\n  ],\n  [\"c\"],\n]",
+		},
+		"List in object": {
+			cty.ObjectVal(map[string]cty.Value{"key": strList, "other": cty.True}),
+			[]tokens.GenerateOption{tokens.WithMultiLine()},
+			"{\n  key = [\n    \"a\",\n    \"b\",\n  ]\n  other = true\n}",
+		},
+		"Object in list": {
+			cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"key": cty.StringVal("a")})}),
+			[]tokens.GenerateOption{tokens.WithMultiLine()},
+			"[\n  {\n    key = \"a\"\n  },\n]",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				actual := string(hclwrite.Format(tokens.Generate(&tcase.value, tcase.opts...).Bytes()))
+				if actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected\n%s\ngot\n%s", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestMergeIterableAndGenerate_withOptions(t *testing.T) {
+	t.Parallel()
+
+	collection := cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("${var.b}")})
+	newElements := []hclwrite.Tokens{tokens.NewIdentTokens("var.c")}
+
+	actual := string(hclwrite.Format(
+		tokens.MergeIterableAndGenerate(collection, newElements, tokens.WithMultiLine()).Bytes(),
+	))
+
+	expected := "[\n  \"a\",\n  \"$${var.b}\",\n  var.c,\n]"
+	if actual != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, actual)
+	}
+}
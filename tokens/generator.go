@@ -11,35 +11,42 @@ import (
 // Generate converts a `cty.Value` to `hclwrite.Tokens`
 //
 // It takes care of special `cty.Value` capsule encapsulating `hclwrite.Tokens`.
-func Generate(valuePtr *cty.Value) hclwrite.Tokens {
-	var value cty.Value
-	if valuePtr != nil {
-		value = *valuePtr
-		valType := value.Type()
+// Provided options are applied to each list, set and tuple, including nested ones (see `GenerateOption`).
+//
+// Marked values are unmarked before being rendered, values marked with SensitiveMark are rendered
+// as a placeholder if `WithRedaction()` option is provided.
+//
+// Null values are rendered as `null`. It panics if the value contains an unknown value (see `ValidateValue()`
+// and `SafeGenerate()` for a version returning an error).
+func Generate(valuePtr *cty.Value, opts ...GenerateOption) hclwrite.Tokens {
+	if valuePtr == nil {
+		return hclwrite.Tokens{}
+	}
 
-		switch {
-		case valType == cty.NilType:
-			// Do nothing, let `hclwrite.TokensForValue()` do the job
-		case valType.IsListType() || valType.IsSetType() || valType.IsTupleType() ||
-			valType.IsMapType() || valType.IsObjectType():
-			if ContainsCapsule(valuePtr) {
-				return generateCapsuleForCollection(valType, value)
+	return generate(*valuePtr, newGenerateConfig(opts))
+}
+
+// SafeGenerate is like `Generate()`, except that it returns an `ErrUnknownValue` error instead of panicking
+// if the value contains an unknown value.
+func SafeGenerate(valuePtr *cty.Value, opts ...GenerateOption) (hclwrite.Tokens, error) {
+	if valuePtr != nil && valuePtr.Type() != cty.NilType {
+		if unmarked, _ := valuePtr.UnmarkDeep(); !unmarked.IsWhollyKnown() {
+			if err := ValidateValue(unmarked); err != nil {
+				return nil, err
 			}
-		case IsCapsuleType(value.Type()):
-			return FromValue(value)
 		}
-	} else {
-		return hclwrite.Tokens{}
 	}
 
-	return hclwrite.TokensForValue(value)
+	return Generate(valuePtr, opts...), nil
 }
 
 // GenerateFromIterable takes a list of `hclwrite.Tokens` and create related `hclwrite.Tokens` based on
 // the provided `cty.Type`
 //
+// Provided options are applied if type is a list, a set or a tuple (see `GenerateOption`).
+//
 // It panics if provided type is not an iterable type.
-func GenerateFromIterable(elements []hclwrite.Tokens, toType cty.Type) hclwrite.Tokens {
+func GenerateFromIterable(elements []hclwrite.Tokens, toType cty.Type, opts ...GenerateOption) hclwrite.Tokens {
 	var emptyCollectionValue cty.Value
 
 	switch {
@@ -57,48 +64,30 @@ func GenerateFromIterable(elements []hclwrite.Tokens, toType cty.Type) hclwrite.
 		panic("expected a collection type but got " + toType.GoString())
 	}
 
-	return MergeIterableAndGenerate(emptyCollectionValue, elements)
+	return MergeIterableAndGenerate(emptyCollectionValue, elements, opts...)
 }
 
 // MergeIterableAndGenerate takes a `cty.Value` collection, append new elements and convert the result
 // to related `hclwrite.Tokens`
 //
+// Provided options are applied if collection is a list, a set or a tuple (see `GenerateOption`).
+//
 // It panics if provided collection is not iterable.
-func MergeIterableAndGenerate(collection cty.Value, newElements []hclwrite.Tokens) hclwrite.Tokens {
+func MergeIterableAndGenerate(
+	collection cty.Value,
+	newElements []hclwrite.Tokens,
+	opts ...GenerateOption,
+) hclwrite.Tokens {
 	tokensStart, existingElements, tokensEnd := SplitIterable(collection)
 
+	if config := newGenerateConfig(opts); isListLikeType(collection.Type()) && !config.isDefault() {
+		return config.buildList(append(splitListElements(existingElements), newElements...))
+	}
+
 	newTokens := existingElements.BuildTokens(tokensStart)
 
 	if len(newElements) > 0 {
-		var separator hclwrite.Tokens
-
-		addSeparator := true
-
-		collectionType := collection.Type()
-		if collectionType.IsListType() || collectionType.IsSetType() || collectionType.IsTupleType() {
-			// Separate elements with a comma for list/set and tuple
-			separator = NewCommaTokens()
-			addSeparator = len(existingElements) > 0
-		} else {
-			// Separate elements with a new line for Objects and Maps
-			// Objects and Maps already have a trailing new line if not empty
-			// => separator must be added only after a new element is added
-			separator = NewLineTokens()
-		}
-
-		for _, elem := range newElements {
-			if addSeparator {
-				newTokens = separator.BuildTokens(newTokens)
-			}
-
-			newTokens = elem.BuildTokens(newTokens)
-			addSeparator = true
-		}
-
-		if addSeparator && (collectionType.IsMapType() || collectionType.IsObjectType()) {
-			// Object and map have a trailing separator
-			newTokens = separator.BuildTokens(newTokens)
-		}
+		newTokens = appendIterableElements(newTokens, collection.Type(), len(existingElements) > 0, newElements)
 	}
 
 	return tokensEnd.BuildTokens(newTokens)
@@ -148,8 +137,52 @@ func SplitIterable(collection cty.Value) (
 
 /** Private **/
 
-func generateCapsuleForCollection(valType cty.Type, value cty.Value) hclwrite.Tokens {
-	isMapOrObjectType := valType.IsMapType() || valType.IsObjectType()
+func generate(value cty.Value, config generateConfig) hclwrite.Tokens {
+	if value.Type() == cty.NilType {
+		// Let `hclwrite.TokensForValue()` do the job
+		return hclwrite.TokensForValue(value)
+	}
+
+	unmarked, pathMarks := value.UnmarkDeepWithPaths()
+	if !unmarked.IsWhollyKnown() {
+		if err := ValidateValue(unmarked); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	if config.redact && hasSensitiveMark(pathMarks) {
+		// Marks must be checked for each nested value => keep the marked value
+		return generateFormatted(value, config)
+	}
+
+	if !config.isDefault() {
+		return generateFormatted(unmarked, config)
+	}
+
+	return generateUnmarked(unmarked, config)
+}
+
+// generateUnmarked converts an unmarked value to `hclwrite.Tokens`, de-encapsulating capsules at any depth.
+func generateUnmarked(unmarked cty.Value, config generateConfig) hclwrite.Tokens {
+	valType := unmarked.Type()
+
+	switch {
+	case isListLikeType(valType) || isMapLikeType(valType):
+		// Null values don't have any element to de-encapsulate
+		if !unmarked.IsNull() && ContainsCapsule(&unmarked) {
+			return generateCapsuleForCollection(valType, unmarked, config)
+		}
+	case IsCapsuleType(valType) && !unmarked.IsNull():
+		return FromValue(unmarked)
+	case IsOrderedObjectType(valType) && !unmarked.IsNull():
+		return generateOrderedObject(FromOrderedObjectValue(unmarked), config)
+	}
+
+	return hclwrite.TokensForValue(unmarked)
+}
+
+func generateCapsuleForCollection(valType cty.Type, value cty.Value, config generateConfig) hclwrite.Tokens {
+	isMapOrObjectType := isMapLikeType(valType)
 	// Generate new element token
 	newElements := make([]hclwrite.Tokens, value.LengthInt())
 	currentIndex := 0
@@ -159,9 +192,9 @@ func generateCapsuleForCollection(valType cty.Type, value cty.Value) hclwrite.To
 
 		eKey, eVal := it.Element()
 		if isMapOrObjectType {
-			tokens = Generate(&eVal).BuildTokens(NewEqualTokens()).BuildTokens(Generate(&eKey))
+			tokens = generate(eVal, config).BuildTokens(NewEqualTokens()).BuildTokens(Generate(&eKey))
 		} else {
-			tokens = Generate(&eVal)
+			tokens = generate(eVal, config)
 		}
 
 		newElements[currentIndex] = tokens
@@ -170,3 +203,50 @@ func generateCapsuleForCollection(valType cty.Type, value cty.Value) hclwrite.To
 
 	return GenerateFromIterable(newElements, valType)
 }
+
+func isListLikeType(valType cty.Type) bool {
+	return valType.IsListType() || valType.IsSetType() || valType.IsTupleType()
+}
+
+func isMapLikeType(valType cty.Type) bool {
+	return valType.IsMapType() || valType.IsObjectType()
+}
+
+// appendIterableElements appends new elements to tokens of an iterable, taking care of separators.
+func appendIterableElements(
+	newTokens hclwrite.Tokens,
+	collectionType cty.Type,
+	hasExistingElements bool,
+	newElements []hclwrite.Tokens,
+) hclwrite.Tokens {
+	var separator hclwrite.Tokens
+
+	addSeparator := true
+
+	if isListLikeType(collectionType) {
+		// Separate elements with a comma for list/set and tuple
+		separator = NewCommaTokens()
+		addSeparator = hasExistingElements
+	} else {
+		// Separate elements with a new line for Objects and Maps
+		// Objects and Maps already have a trailing new line if not empty
+		// => separator must be added only after a new element is added
+		separator = NewLineTokens()
+	}
+
+	for _, elem := range newElements {
+		if addSeparator {
+			newTokens = separator.BuildTokens(newTokens)
+		}
+
+		newTokens = elem.BuildTokens(newTokens)
+		addSeparator = true
+	}
+
+	if addSeparator && isMapLikeType(collectionType) {
+		// Object and map have a trailing separator
+		newTokens = separator.BuildTokens(newTokens)
+	}
+
+	return newTokens
+}
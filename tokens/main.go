@@ -31,26 +31,31 @@ func NewIdentValue(s string) *cty.Value {
 	return &val
 }
 
-// NewIdentListValue takes a list of string which should be all considered as 'ident' tokens
-// and converts them into a cty list containing special `cty.Value` capsule.
-func NewIdentListValue(list []string) *cty.Value {
-	if list == nil {
-		return nil
+// NewExpressionValue takes a string which should be considered as an HCL expression and converts it
+// to a special `cty.Value` capsule holding the actual expression tokens (see `FromExpression()`).
+//
+// It falls back on `NewIdentValue()` if provided string is not a valid HCL expression.
+func NewExpressionValue(s string) *cty.Value {
+	exprTokens, err := FromExpression(s)
+	if err != nil {
+		return NewIdentValue(s)
 	}
 
-	val := cty.ListValEmpty(hclwriteTokensCtyType)
+	val := ToValue(exprTokens)
 
-	listLength := len(list)
-	if listLength > 0 {
-		newList := make([]cty.Value, listLength)
-		for i, s := range list {
-			newList[i] = ToValue(NewIdentTokens(s))
-		}
+	return &val
+}
 
-		val = cty.ListVal(newList)
-	}
+// NewIdentListValue takes a list of string which should be all considered as 'ident' tokens
+// and converts them into a cty list containing special `cty.Value` capsule.
+func NewIdentListValue(list []string) *cty.Value {
+	return newListValue(list, NewIdentValue)
+}
 
-	return &val
+// NewExpressionListValue takes a list of string which should be all considered as HCL expressions
+// and converts them into a cty list containing special `cty.Value` capsule (see `NewExpressionValue()`).
+func NewExpressionListValue(list []string) *cty.Value {
+	return newListValue(list, NewExpressionValue)
 }
 
 // ToValue takes `hclwrite.Tokens` value and converts it to special `cty.Value` capsule.
@@ -69,3 +74,25 @@ func FromValue(v cty.Value) hclwrite.Tokens {
 
 	return newTokens
 }
+
+/** Private **/
+
+func newListValue(list []string, toValue func(s string) *cty.Value) *cty.Value {
+	if list == nil {
+		return nil
+	}
+
+	val := cty.ListValEmpty(hclwriteTokensCtyType)
+
+	listLength := len(list)
+	if listLength > 0 {
+		newList := make([]cty.Value, listLength)
+		for i, s := range list {
+			newList[i] = *toValue(s)
+		}
+
+		val = cty.ListVal(newList)
+	}
+
+	return &val
+}
@@ -0,0 +1,53 @@
+package tokens
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Mark is the type of cty marks managed by `Generate()` (see `cty.Value.Mark()`).
+type Mark string
+
+// SensitiveMark is the cty mark for sensitive values (e.g. `cty.StringVal("secret").Mark(tokens.SensitiveMark)`),
+// those values are rendered as RedactedPlaceholder string if `WithRedaction()` option is provided.
+const SensitiveMark Mark = "sensitive"
+
+// RedactedPlaceholder is the string rendered instead of values marked with SensitiveMark (see `WithRedaction()`).
+const RedactedPlaceholder = "(sensitive)"
+
+// WithRedaction renders values marked with SensitiveMark, at any depth, as RedactedPlaceholder string
+// (e.g. for logs or previews).
+func WithRedaction() GenerateOption {
+	return func(c *generateConfig) {
+		c.redact = true
+	}
+}
+
+// IsSensitive returns true if provided value, or one of its nested values, is marked with SensitiveMark.
+func IsSensitive(value cty.Value) bool {
+	_, pathMarks := value.UnmarkDeepWithPaths()
+
+	return hasSensitiveMark(pathMarks)
+}
+
+/** Private **/
+
+func hasSensitiveMark(pathMarks []cty.PathValueMarks) bool {
+	for _, pathMark := range pathMarks {
+		if hasMark(pathMark.Marks, SensitiveMark) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasMark(marks cty.ValueMarks, mark Mark) bool {
+	_, exists := marks[mark]
+
+	return exists
+}
+
+func newRedactedTokens() hclwrite.Tokens {
+	return hclwrite.TokensForValue(cty.StringVal(RedactedPlaceholder))
+}
@@ -0,0 +1,103 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestGenerate_marks(t *testing.T) {
+	t.Parallel()
+
+	secret := cty.StringVal("secret").Mark(tokens.SensitiveMark)
+	otherMark := cty.StringVal("value").Mark("other")
+
+	cases := map[string]struct {
+		value            cty.Value
+		opts             []tokens.GenerateOption
+		expected         string
+		expectedRedacted string
+	}{
+		"Primitive":  {secret, nil, `"secret"`, `"(sensitive)"`},
+		"Other mark": {otherMark, nil, `"value"`, `"value"`},
+		"Marked list": {
+			cty.ListVal([]cty.Value{cty.NumberIntVal(1)}).Mark(tokens.SensitiveMark),
+			nil,
+			"[1]",
+			`"(sensitive)"`,
+		},
+		"In a list": {
+			cty.ListVal([]cty.Value{cty.StringVal("a"), secret}),
+			nil,
+			`["a", "secret"]`,
+			`["a", "(sensitive)"]`,
+		},
+		"In a number list": {
+			cty.ListVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2).Mark(tokens.SensitiveMark)}),
+			nil,
+			`[1, 2]`,
+			`[1, "(sensitive)"]`,
+		},
+		"In an object": {
+			cty.ObjectVal(map[string]cty.Value{"password": secret, "user": otherMark}),
+			nil,
+			"{\n  password = \"secret\"\n  user     = \"value\"\n}",
+			"{\n  password = \"(sensitive)\"\n  user     = \"value\"\n}",
+		},
+		"With capsule": {
+			cty.TupleVal([]cty.Value{*tokens.NewIdentValue("var.a"), secret}),
+			nil,
+			`[var.a, "secret"]`,
+			`[var.a, "(sensitive)"]`,
+		},
+		"In an ordered object": {
+			tokens.NewOrderedObjectValue(tokens.NewObjectEntry("b", secret), tokens.NewObjectEntry("a", cty.True)),
+			nil,
+			"{\n  b = \"secret\"\n  a = true\n}",
+			"{\n  b = \"(sensitive)\"\n  a = true\n}",
+		},
+		"With layout options": {
+			cty.ListVal([]cty.Value{cty.StringVal("a"), secret}),
+			[]tokens.GenerateOption{tokens.WithMultiLine()},
+			"[\n  \"a\",\n  \"secret\",\n]",
+			"[\n  \"a\",\n  \"(sensitive)\",\n]",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				actual := string(hclwrite.Format(tokens.Generate(&tcase.value, tcase.opts...).Bytes()))
+				if actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected\n%s\ngot\n%s", t.Name(), tcase.expected, actual)
+				}
+
+				redactedOpts := append([]tokens.GenerateOption{tokens.WithRedaction()}, tcase.opts...)
+
+				actual = string(hclwrite.Format(tokens.Generate(&tcase.value, redactedOpts...).Bytes()))
+				if actual != tcase.expectedRedacted {
+					t.Errorf("Case \"%s\": expected redacted\n%s\ngot\n%s", t.Name(), tcase.expectedRedacted, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestIsSensitive(t *testing.T) {
+	t.Parallel()
+
+	nested := cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("secret").Mark(tokens.SensitiveMark)})
+	if !tokens.IsSensitive(nested) {
+		t.Error("expected nested sensitive value to be detected")
+	}
+
+	if tokens.IsSensitive(cty.StringVal("value").Mark("other")) {
+		t.Error("expected other marks to be ignored")
+	}
+}
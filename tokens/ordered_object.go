@@ -0,0 +1,110 @@
+package tokens
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+// OrderedObjectCtyTypeName is the friendly cty name for the capsule encapsulating an OrderedObject.
+const OrderedObjectCtyTypeName = "cty.CapsuleVal(tokens.OrderedObject)"
+
+//nolint:gochecknoglobals // Better to keep it as **internal** global var than define it each time
+var orderedObjectCtyType = cty.Capsule(OrderedObjectCtyTypeName, reflect.TypeOf(OrderedObject{}))
+
+// ObjectEntry is a key/value pair of an OrderedObject.
+type ObjectEntry struct {
+	key       string
+	keyTokens hclwrite.Tokens
+	value     cty.Value
+}
+
+// NewObjectEntry returns an ObjectEntry whose key is rendered as a bare or a quoted key (see `NewObjectKeyTokens()`).
+func NewObjectEntry(key string, value cty.Value) ObjectEntry {
+	return ObjectEntry{key: key, keyTokens: NewObjectKeyTokens(key), value: value}
+}
+
+// NewExpressionObjectEntry returns an ObjectEntry whose key is an HCL expression rendered wrapped into parentheses
+// (see `NewExpressionObjectKeyTokens()`).
+func NewExpressionObjectEntry(expr string, value cty.Value) ObjectEntry {
+	return ObjectEntry{key: expr, keyTokens: NewExpressionObjectKeyTokens(expr), value: value}
+}
+
+// GetKey returns the key of the entry.
+func (e ObjectEntry) GetKey() string {
+	return e.key
+}
+
+// GetValue returns the value of the entry.
+func (e ObjectEntry) GetValue() cty.Value {
+	return e.value
+}
+
+// WithValue returns a copy of the entry holding the provided value (key is kept as is).
+func (e ObjectEntry) WithValue(value cty.Value) ObjectEntry {
+	e.value = value
+
+	return e
+}
+
+// OrderedObject is a list of ObjectEntry rendered as an object, keys are rendered in the provided order
+// (unlike `cty.ObjectVal()` and `cty.MapVal()` which sort them).
+type OrderedObject []ObjectEntry
+
+// NewOrderedObjectValue takes a list of ObjectEntry and converts it to a special `cty.Value` capsule
+// encapsulating an OrderedObject
+//
+// Values are converted to tokens only when the capsule is rendered (see `Generate()`), entries with a duplicated key
+// override the value of the first entry with that key.
+func NewOrderedObjectValue(entries ...ObjectEntry) cty.Value {
+	object := OrderedObject{}
+	positions := map[string]int{}
+
+	for _, entry := range entries {
+		if pos, exists := positions[entry.key]; exists {
+			object[pos] = entry
+
+			continue
+		}
+
+		positions[entry.key] = len(object)
+		object = append(object, entry)
+	}
+
+	return cty.CapsuleVal(orderedObjectCtyType, &object)
+}
+
+// IsOrderedObjectType returns true if provided `cty.Type` is a special capsule encapsulating an OrderedObject.
+func IsOrderedObjectType(t cty.Type) bool {
+	return t.IsCapsuleType() && t.FriendlyName() == OrderedObjectCtyTypeName
+}
+
+// FromOrderedObjectValue takes a `cty.Value` and extract the OrderedObject from it.
+//
+// It panics if the provided value is not a special `cty.Value` capsule encapsulating an OrderedObject.
+func FromOrderedObjectValue(v cty.Value) OrderedObject {
+	object := OrderedObject{}
+	if err := gocty.FromCtyValue(v, &object); err != nil {
+		panic(fmt.Sprintf("error during conversion from cty.Value to tokens.OrderedObject: %s", err))
+	}
+
+	return object
+}
+
+/** Private **/
+
+func generateOrderedObject(object OrderedObject, config generateConfig) hclwrite.Tokens {
+	elements := make([]hclwrite.Tokens, len(object))
+
+	for idx, entry := range object {
+		childConfig := config.nested(config.lineIndent + indentSize + len(entry.keyTokens.Bytes()) + len(" = "))
+		valueTokens := generate(entry.value, childConfig)
+
+		elements[idx] = valueTokens.BuildTokens(NewEqualTokens().BuildTokens(entry.keyTokens))
+	}
+
+	return GenerateFromIterable(elements, cty.EmptyObject)
+}
@@ -0,0 +1,91 @@
+package tokens_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestNewOrderedObjectValue(t *testing.T) {
+	t.Parallel()
+
+	nested := tokens.NewOrderedObjectValue(
+		tokens.NewObjectEntry("Z", cty.True),
+		tokens.NewObjectEntry("A", cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})),
+	)
+
+	cases := map[string]struct {
+		value    cty.Value
+		opts     []tokens.GenerateOption
+		expected string
+	}{
+		"Empty": {tokens.NewOrderedObjectValue(), nil, "{}"},
+		"Keys order": {
+			tokens.NewOrderedObjectValue(
+				tokens.NewObjectEntry("Name", *tokens.NewIdentValue("var.name")),
+				tokens.NewObjectEntry("my key", cty.StringVal("value")),
+				tokens.NewExpressionObjectEntry("var.key", cty.NumberIntVal(1)),
+				tokens.NewObjectEntry("Env", cty.StringVal("prod")),
+			),
+			nil,
+			"{\n  Name      = var.name\n  \"my key\"  = \"value\"\n  (var.key) = 1\n  Env       = \"prod\"\n}",
+		},
+		"Duplicated key": {
+			tokens.NewOrderedObjectValue(
+				tokens.NewObjectEntry("B", cty.StringVal("b")),
+				tokens.NewObjectEntry("A", cty.StringVal("a")),
+				tokens.NewObjectEntry("B", cty.StringVal("bb")),
+			),
+			nil,
+			"{\n  B = \"bb\"\n  A = \"a\"\n}",
+		},
+		"Nested": {
+			cty.TupleVal([]cty.Value{nested}),
+			nil,
+			"[{\n  Z = true\n  A = [\"a\", \"b\"]\n}]",
+		},
+		"Nested with options": {
+			cty.TupleVal([]cty.Value{nested}),
+			[]tokens.GenerateOption{tokens.WithMaxElements(1)},
+			"[{\n  Z = true\n  A = [\n    \"a\",\n    \"b\",\n  ]\n}]",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if !tokens.ContainsCapsule(&tcase.value) {
+					t.Fatalf("Case \"%s\": expected value to contain a capsule", t.Name())
+				}
+
+				actual := string(hclwrite.Format(tokens.Generate(&tcase.value, tcase.opts...).Bytes()))
+				if actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected\n%s\ngot\n%s", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
+
+func TestFromOrderedObjectValue(t *testing.T) {
+	t.Parallel()
+
+	object := tokens.FromOrderedObjectValue(tokens.NewOrderedObjectValue(tokens.NewObjectEntry("A", cty.True)))
+	if len(object) != 1 || object[0].GetKey() != "A" || !object[0].GetValue().RawEquals(cty.True) {
+		t.Errorf("unexpected object %#v", object)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic")
+		}
+	}()
+
+	tokens.FromOrderedObjectValue(cty.True)
+}
@@ -1,6 +1,9 @@
 package tokens
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -10,30 +13,29 @@ func IsCapsuleType(t cty.Type) bool {
 }
 
 // ContainsCapsule will deep check if provided value contains a special capsule encapsulating `hclwrite.Tokens`
-// (and therefore requires special process to de-encapsulate it).
+// or an OrderedObject (and therefore requires special process to de-encapsulate it).
+//
+// Any nesting of list, set, map, tuple and object is checked (see `TypeContainsCapsule()`).
 func ContainsCapsule(valPtr *cty.Value) bool {
 	if valPtr == nil {
 		return false
 	}
 
-	val := *valPtr
-	valType := val.Type()
+	return TypeContainsCapsule(valPtr.Type())
+}
 
+// TypeContainsCapsule will recursively check if provided type is, or contains at any depth, a special capsule type
+// encapsulating `hclwrite.Tokens` or an OrderedObject.
+func TypeContainsCapsule(valType cty.Type) bool {
 	switch {
-	case valType.IsListType() || valType.IsSetType() || valType.IsMapType():
-		// Map, List and Set contain only one type of value
-		return IsCapsuleType(valType.ElementType())
-	case valType.IsTupleType() || valType.IsObjectType():
-		// Tuple and Object contain multiple value type => iterate over each of them and check
-		for it := val.ElementIterator(); it.Next(); {
-			_, eVal := it.Element()
-			if ContainsCapsule(&eVal) {
-				return true
-			}
-		}
-
-	case IsCapsuleType(valType):
+	case IsCapsuleType(valType), IsOrderedObjectType(valType):
 		return true
+	case valType.IsListType() || valType.IsSetType() || valType.IsMapType():
+		return TypeContainsCapsule(valType.ElementType())
+	case valType.IsTupleType():
+		return slices.ContainsFunc(valType.TupleElementTypes(), TypeContainsCapsule)
+	case valType.IsObjectType():
+		return slices.ContainsFunc(slices.Collect(maps.Values(valType.AttributeTypes())), TypeContainsCapsule)
 	}
 
 	return false
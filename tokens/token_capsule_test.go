@@ -1,8 +1,12 @@
 package tokens_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/yoanm/go-tfsig/tokens"
 )
 
@@ -13,3 +17,104 @@ func TestContainsCapsule_nil(t *testing.T) {
 		t.Errorf("expected false, got  true")
 	}
 }
+
+// newCollectionWrappers returns functions wrapping a value into each kind of collection, indexed by kind.
+func newCollectionWrappers() map[string]func(cty.Value) cty.Value {
+	return map[string]func(cty.Value) cty.Value{
+		"list":   func(v cty.Value) cty.Value { return cty.ListVal([]cty.Value{v}) },
+		"set":    func(v cty.Value) cty.Value { return cty.SetVal([]cty.Value{v}) },
+		"map":    func(v cty.Value) cty.Value { return cty.MapVal(map[string]cty.Value{"key": v}) },
+		"tuple":  func(v cty.Value) cty.Value { return cty.TupleVal([]cty.Value{v, cty.StringVal("other")}) },
+		"object": func(v cty.Value) cty.Value { return cty.ObjectVal(map[string]cty.Value{"key": v, "z": cty.True}) },
+	}
+}
+
+func TestContainsCapsule_nested(t *testing.T) {
+	t.Parallel()
+
+	collectionWrappers := newCollectionWrappers()
+
+	for outerName, outer := range collectionWrappers {
+		for innerName, inner := range collectionWrappers {
+			t.Run(
+				outerName+" of "+innerName,
+				func(t *testing.T) {
+					t.Parallel()
+
+					withCapsule := outer(inner(*tokens.NewIdentValue("var.x")))
+					if !tokens.ContainsCapsule(&withCapsule) {
+						t.Errorf("Case \"%s\": expected true, got false", t.Name())
+					}
+
+					withoutCapsule := outer(inner(cty.StringVal("x")))
+					if tokens.ContainsCapsule(&withoutCapsule) {
+						t.Errorf("Case \"%s\": expected false, got true", t.Name())
+					}
+				},
+			)
+		}
+	}
+}
+
+func TestGenerate_nested(t *testing.T) {
+	t.Parallel()
+
+	collectionWrappers := newCollectionWrappers()
+
+	for outerName, outer := range collectionWrappers {
+		for innerName, inner := range collectionWrappers {
+			t.Run(
+				outerName+" of "+innerName,
+				func(t *testing.T) {
+					t.Parallel()
+
+					value := outer(inner(*tokens.NewIdentValue("var.x")))
+					// Same structure rendered by hclwrite, with a placeholder instead of the capsule
+					reference := hclwrite.TokensForValue(outer(inner(cty.StringVal("__placeholder__"))))
+					expected := strings.ReplaceAll(
+						string(hclwrite.Format(reference.Bytes())),
+						`"__placeholder__"`,
+						"var.x",
+					)
+
+					// Unlike hclwrite, object keys of collections holding a capsule are rendered as quoted strings
+					actual := strings.NewReplacer(`"key"`, "key", `"z"`, "z").Replace(
+						string(hclwrite.Format(tokens.Generate(&value).Bytes())),
+					)
+					if actual != expected {
+						t.Errorf("Case \"%s\": expected\n%s\ngot\n%s", t.Name(), expected, actual)
+					}
+				},
+			)
+		}
+	}
+}
+
+func TestGenerate_nullWithCapsuleType(t *testing.T) {
+	t.Parallel()
+
+	capsuleType := tokens.NewIdentValue("var.x").Type()
+
+	cases := map[string]struct {
+		value    cty.Value
+		expected string
+	}{
+		"capsule":         {cty.NullVal(capsuleType), "null"},
+		"list":            {cty.NullVal(cty.List(capsuleType)), "null"},
+		"object":          {cty.NullVal(cty.Object(map[string]cty.Type{"key": capsuleType})), "null"},
+		"null in a tuple": {cty.TupleVal([]cty.Value{cty.NullVal(capsuleType)}), "[null]"},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := string(tokens.Generate(&tcase.value).Bytes()); actual != tcase.expected {
+					t.Errorf("Case \"%s\": expected %q, got %q", t.Name(), tcase.expected, actual)
+				}
+			},
+		)
+	}
+}
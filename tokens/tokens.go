@@ -1,9 +1,18 @@
 package tokens
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 )
 
+// ErrInvalidExpression is returned by `FromExpression()` when provided string is not a valid HCL expression.
+var ErrInvalidExpression = errors.New("invalid expression")
+
 // NewIdentTokens takes a string and convert it to `hclwrite.Tokens` containing a `hclwrite.Token`
 // with `hclsyntax.TokenIdent` type
 //
@@ -12,6 +21,67 @@ func NewIdentTokens(s string) hclwrite.Tokens {
 	return hclwrite.Tokens{NewIdentToken([]byte(s))}
 }
 
+// FromExpression takes an HCL expression (e.g. `data.x.y[0].id` or `var.a + 1`) and converts it to `hclwrite.Tokens`
+// containing the actual tokens of the expression (idents, dots, brackets, operators, strings, etc)
+//
+// Unlike `NewIdentTokens()`, it allows `hclwrite.Format()` to properly manage spacing.
+// It returns an error if provided string is not a valid HCL expression.
+func FromExpression(src string) (hclwrite.Tokens, error) {
+	srcBytes := []byte(src)
+
+	if _, diags := hclsyntax.ParseExpression(srcBytes, "", hcl.InitialPos); diags.HasErrors() {
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidExpression, src, diags.Error())
+	}
+
+	syntaxTokens, diags := hclsyntax.LexExpression(srcBytes, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w %q: %s", ErrInvalidExpression, src, diags.Error())
+	}
+
+	newTokens := make(hclwrite.Tokens, 0, len(syntaxTokens))
+	lastEnd := hcl.InitialPos
+
+	for _, token := range syntaxTokens {
+		if token.Type == hclsyntax.TokenEOF {
+			break
+		}
+
+		newTokens = append(newTokens, &hclwrite.Token{
+			Type:         token.Type,
+			Bytes:        token.Bytes,
+			SpacesBefore: spacesBetween(lastEnd, token.Range.Start),
+		})
+		lastEnd = token.Range.End
+	}
+
+	return newTokens, nil
+}
+
+// NewObjectKeyTokens takes an object key and converts it to `hclwrite.Tokens`
+//
+// Key is rendered as is if it is a valid identifier, else as a quoted string (like `hclwrite.TokensForValue()` does).
+func NewObjectKeyTokens(key string) hclwrite.Tokens {
+	if hclsyntax.ValidIdentifier(key) {
+		return NewIdentTokens(key)
+	}
+
+	return hclwrite.TokensForValue(cty.StringVal(key))
+}
+
+// NewExpressionObjectKeyTokens takes an HCL expression which should be used as object key and converts it
+// to `hclwrite.Tokens` wrapped into parentheses (e.g. `(var.key)`), as required by HCL for non-literal keys.
+func NewExpressionObjectKeyTokens(expr string) hclwrite.Tokens {
+	exprTokens, err := FromExpression(expr)
+	if err != nil {
+		exprTokens = NewIdentTokens(expr)
+	}
+
+	keyTokens := hclwrite.Tokens{{Type: hclsyntax.TokenOParen, Bytes: []byte{'('}, SpacesBefore: 0}}
+	keyTokens = append(keyTokens, exprTokens...)
+
+	return append(keyTokens, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte{')'}, SpacesBefore: 0})
+}
+
 // NewCommaTokens creates a `hclwrite.Tokens` containing a `hclwrite.Token` with `hclsyntax.TokenComma` type
 //
 // See also `NewCommaToken()`.
@@ -32,3 +102,16 @@ func NewEqualTokens() hclwrite.Tokens {
 func NewLineTokens() hclwrite.Tokens {
 	return hclwrite.Tokens{NewLineToken()}
 }
+
+/** Private **/
+
+// spacesBetween returns the number of spaces between the end of a token and the start of the next one
+//
+// Gap is computed only if both are on the same line, else it is the indentation of the next token.
+func spacesBetween(end, start hcl.Pos) int {
+	if end.Line != start.Line {
+		return start.Column - 1
+	}
+
+	return start.Column - end.Column
+}
@@ -0,0 +1,133 @@
+package tokens_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestFromExpression(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value         string
+		expectedTypes []hclsyntax.TokenType
+		formatted     string
+	}{
+		"Traversal": {
+			"data.x.y[0].id",
+			[]hclsyntax.TokenType{
+				hclsyntax.TokenIdent, hclsyntax.TokenDot, hclsyntax.TokenIdent, hclsyntax.TokenDot, hclsyntax.TokenIdent,
+				hclsyntax.TokenOBrack, hclsyntax.TokenNumberLit, hclsyntax.TokenCBrack, hclsyntax.TokenDot,
+				hclsyntax.TokenIdent,
+			},
+			"data.x.y[0].id",
+		},
+		"Operators": {
+			"var.a+1",
+			[]hclsyntax.TokenType{
+				hclsyntax.TokenIdent, hclsyntax.TokenDot, hclsyntax.TokenIdent, hclsyntax.TokenPlus,
+				hclsyntax.TokenNumberLit,
+			},
+			"var.a + 1",
+		},
+		"Function and string": {
+			`lower( "A" )`,
+			[]hclsyntax.TokenType{
+				hclsyntax.TokenIdent, hclsyntax.TokenOParen, hclsyntax.TokenOQuote, hclsyntax.TokenQuotedLit,
+				hclsyntax.TokenCQuote, hclsyntax.TokenCParen,
+			},
+			`lower("A")`,
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				actual, err := tokens.FromExpression(tcase.value)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", t.Name(), err)
+				}
+
+				actualTypes := make([]hclsyntax.TokenType, len(actual))
+				for idx, token := range actual {
+					actualTypes[idx] = token.Type
+				}
+
+				if !reflect.DeepEqual(tcase.expectedTypes, actualTypes) {
+					t.Errorf("Case \"%s\": expected types %v, got %v", t.Name(), tcase.expectedTypes, actualTypes)
+				}
+
+				hclFile := hclwrite.NewEmptyFile()
+				hclFile.Body().SetAttributeRaw("attr", actual)
+
+				if expected := "attr = " + tcase.formatted + "\n"; string(hclFile.Bytes()) != expected {
+					t.Errorf("Case \"%s\": expected %q, got %q", t.Name(), expected, string(hclFile.Bytes()))
+				}
+			},
+		)
+	}
+}
+
+func TestFromExpression_spaces(t *testing.T) {
+	t.Parallel()
+
+	actual, err := tokens.FromExpression("{\n  a = \"é\"  +  1\n    b = [\n  var.a ]\n}")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	actualSpaces := make([]int, len(actual))
+	for idx, token := range actual {
+		actualSpaces[idx] = token.SpacesBefore
+	}
+
+	// {, \n, a, =, ", é, ", +, 1, \n, b, =, [, \n, var, ., a, ], \n, }
+	expected := []int{0, 0, 2, 1, 1, 0, 0, 2, 2, 0, 4, 1, 1, 0, 2, 0, 0, 1, 0, 0}
+	if !reflect.DeepEqual(expected, actualSpaces) {
+		t.Errorf("expected %v, got %v", expected, actualSpaces)
+	}
+}
+
+func TestFromExpression_error(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"var.x is cool", "var.", `"unterminated`} {
+		if _, err := tokens.FromExpression(value); !errors.Is(err, tokens.ErrInvalidExpression) {
+			t.Errorf("Case %q: expected %v, got %v", value, tokens.ErrInvalidExpression, err)
+		}
+	}
+}
+
+func TestNewExpressionValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		expected int
+	}{
+		"Valid expression":   {"var.a+1", 5},
+		"Invalid expression": {"var.x is cool", 1},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				if actual := tokens.FromValue(*tokens.NewExpressionValue(tcase.value)); len(actual) != tcase.expected {
+					t.Errorf("Case \"%s\": expected %d tokens, got %d", t.Name(), tcase.expected, len(actual))
+				}
+			},
+		)
+	}
+}
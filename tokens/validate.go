@@ -0,0 +1,70 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ErrUnknownValue is returned by `ValidateValue()` when a value is unknown (e.g. `cty.DynamicVal`),
+// HCL has no syntax for such values.
+var ErrUnknownValue = errors.New("unknown values can't be rendered")
+
+// ValidateValue returns an `ErrUnknownValue` error if provided value is, or contains at any depth, an unknown value
+//
+// Values encapsulated into an OrderedObject are checked too. Null values are valid and rendered as `null`.
+func ValidateValue(value cty.Value) error {
+	return validateValue(value, cty.Path{})
+}
+
+/** Private **/
+
+func validateValue(value cty.Value, basePath cty.Path) error {
+	//nolint:wrapcheck // Errors are created by the callback
+	return cty.Walk(value, func(path cty.Path, val cty.Value) (bool, error) {
+		fullPath := append(basePath.Copy(), path...)
+
+		if !val.IsKnown() {
+			return false, fmt.Errorf("%w (%s at %s)", ErrUnknownValue, val.Type().FriendlyName(), formatPath(fullPath))
+		}
+
+		if IsOrderedObjectType(val.Type()) && !val.IsNull() {
+			for _, entry := range FromOrderedObjectValue(val) {
+				if err := validateValue(entry.value, fullPath.GetAttr(entry.key)); err != nil {
+					return false, err
+				}
+			}
+		}
+
+		return true, nil
+	})
+}
+
+// formatPath returns a path like `.attr["key"][0]` (or `value` for the root).
+func formatPath(path cty.Path) string {
+	if len(path) == 0 {
+		return "value"
+	}
+
+	var buf strings.Builder
+
+	for _, step := range path {
+		switch typed := step.(type) {
+		case cty.GetAttrStep:
+			buf.WriteString("." + typed.Name)
+		case cty.IndexStep:
+			switch typed.Key.Type() {
+			case cty.String:
+				buf.WriteString(fmt.Sprintf("[%q]", typed.Key.AsString()))
+			case cty.Number:
+				buf.WriteString("[" + typed.Key.AsBigFloat().Text('f', -1) + "]")
+			default:
+				buf.WriteString("[*]")
+			}
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,92 @@
+package tokens_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestValidateValue(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value        cty.Value
+		expectedPath string
+	}{
+		"Known":        {cty.ObjectVal(map[string]cty.Value{"a": cty.ListVal([]cty.Value{cty.True})}), ""},
+		"Null":         {cty.NullVal(cty.DynamicPseudoType), ""},
+		"Capsule":      {*tokens.NewIdentValue("var.a"), ""},
+		"Dynamic":      {cty.DynamicVal, "dynamic at value"},
+		"Unknown":      {cty.UnknownVal(cty.String), "string at value"},
+		"In an object": {cty.ObjectVal(map[string]cty.Value{"a": cty.UnknownVal(cty.Bool)}), "bool at .a"},
+		"In a map":     {cty.MapVal(map[string]cty.Value{"my key": cty.UnknownVal(cty.Bool)}), `bool at ["my key"]`},
+		"In a list": {
+			cty.TupleVal([]cty.Value{cty.True, cty.ListVal([]cty.Value{cty.UnknownVal(cty.Number)})}),
+			"number at [1][0]",
+		},
+		"In an ordered object": {
+			cty.TupleVal([]cty.Value{tokens.NewOrderedObjectValue(tokens.NewObjectEntry("a", cty.DynamicVal))}),
+			"dynamic at [0].a",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				err := tokens.ValidateValue(tcase.value)
+
+				switch {
+				case tcase.expectedPath == "" && err != nil:
+					t.Errorf("Case \"%s\": unexpected error %v", t.Name(), err)
+				case tcase.expectedPath != "" && !errors.Is(err, tokens.ErrUnknownValue):
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tokens.ErrUnknownValue, err)
+				case tcase.expectedPath != "" && !strings.Contains(err.Error(), "("+tcase.expectedPath+")"):
+					t.Errorf("Case \"%s\": expected path %q, got %q", t.Name(), tcase.expectedPath, err)
+				}
+			},
+		)
+	}
+}
+
+func TestGenerate_unknown(t *testing.T) {
+	t.Parallel()
+
+	value := cty.ObjectVal(map[string]cty.Value{"a": cty.DynamicVal})
+
+	testutils.ExpectPanic(
+		t,
+		"Unknown",
+		func() {
+			tokens.Generate(&value)
+		},
+		"unknown values can't be rendered (dynamic at .a)",
+	)
+}
+
+func TestSafeGenerate(t *testing.T) {
+	t.Parallel()
+
+	unknown := cty.ObjectVal(map[string]cty.Value{"a": cty.DynamicVal})
+	if _, err := tokens.SafeGenerate(&unknown); !errors.Is(err, tokens.ErrUnknownValue) {
+		t.Errorf("expected %v, got %v", tokens.ErrUnknownValue, err)
+	}
+
+	known := cty.StringVal("value").Mark(tokens.SensitiveMark)
+
+	tks, err := tokens.SafeGenerate(&known)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if actual := string(tks.Bytes()); actual != `"value"` {
+		t.Errorf("expected %q, got %q", `"value"`, actual)
+	}
+}
@@ -40,10 +40,18 @@ func AppendAttributeIfNotNil(sig *BlockSignature, attrName string, v *cty.Value)
 // It simply avoids two `if` in your code.
 func AppendChildIfNotNil(sig *BlockSignature, child *BlockSignature) {
 	if child != nil {
-		if len(sig.GetElements()) > 0 {
-			sig.AppendEmptyLine()
-		}
+		appendSeparatedChild(sig, child)
+	}
+}
 
-		sig.AppendChild(child)
+/** Private **/
+
+// appendSeparatedChild appends the provided child to the signature, prepended by an empty line
+// in case there is existing elements.
+func appendSeparatedChild(sig *BlockSignature, child *BlockSignature) {
+	if len(sig.GetElements()) > 0 {
+		sig.AppendEmptyLine()
 	}
+
+	sig.AppendChild(child)
 }
@@ -0,0 +1,45 @@
+package tfsig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// Validate returns an error for each attribute of the block, or of its children, which can't be rendered
+// (e.g. unknown values, see `tokens.ValidateValue()`).
+func (sig *BlockSignature) Validate() error {
+	return validateElements([]string{sig.GetHeader()}, sig.GetElements())
+}
+
+// Validate returns an error for each attribute of the file which can't be rendered (see `BlockSignature.Validate()`).
+func (f *FileSignature) Validate() error {
+	return validateElements([]string{}, f.GetElements())
+}
+
+/** Private **/
+
+func validateElements(path []string, elements BodyElements) error {
+	errs := []error{}
+
+	for _, elem := range elements {
+		switch {
+		case elem.IsBodyAttribute():
+			if err := tokens.ValidateValue(*elem.GetBodyAttribute()); err != nil {
+				attrPath := strings.Join(append(append([]string{}, path...), elem.GetName()), " > ")
+				errs = append(errs, fmt.Errorf("%s: %w", attrPath, err))
+			}
+		case elem.IsBodyBlock():
+			child := elem.GetBodyBlock()
+			childPath := append(append([]string{}, path...), child.GetHeader())
+
+			if err := validateElements(childPath, child.GetElements()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,95 @@
+package tfsig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func TestBlockSignature_Validate(t *testing.T) {
+	t.Parallel()
+
+	child := tfsig.NewSignature("child")
+	child.AppendAttribute("unknown", cty.UnknownVal(cty.String))
+
+	sig := tfsig.NewResource("res_name", "res_id")
+	sig.AppendAttribute("null", tfsig.Null())
+	sig.AppendAttribute("dynamic", cty.DynamicVal)
+	sig.AppendChild(child)
+
+	err := sig.Validate()
+	if !errors.Is(err, tokens.ErrUnknownValue) {
+		t.Fatalf("expected %v, got %v", tokens.ErrUnknownValue, err)
+	}
+
+	expected := `resource "res_name" "res_id" > dynamic: unknown values can't be rendered (dynamic at value)
+resource "res_name" "res_id" > child > unknown: unknown values can't be rendered (string at value)`
+	if err.Error() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, err)
+	}
+
+	if err = tfsig.NewFileSignature(tfsig.NewSignature("valid")).Validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestBlockSignature_Build_unknown(t *testing.T) {
+	t.Parallel()
+
+	sig := tfsig.NewSignature("sig")
+	sig.AppendAttribute("attr", cty.ListVal([]cty.Value{cty.UnknownVal(cty.String)}))
+
+	testutils.ExpectPanic(
+		t,
+		"Unknown",
+		func() {
+			sig.Build()
+		},
+		"attr: unknown values can't be rendered (string at [0])",
+	)
+}
+
+func TestSafeBuild(t *testing.T) {
+	t.Parallel()
+
+	sig := tfsig.NewSignature("sig")
+	sig.AppendAttribute("attr", cty.ListVal([]cty.Value{cty.UnknownVal(cty.String)}))
+
+	if _, err := sig.SafeBuild(); !errors.Is(err, tokens.ErrUnknownValue) {
+		t.Errorf("expected %v, got %v", tokens.ErrUnknownValue, err)
+	}
+
+	file := tfsig.NewFileSignature(sig)
+	if _, err := file.SafeBytes(); !errors.Is(err, tokens.ErrUnknownValue) {
+		t.Errorf("expected %v, got %v", tokens.ErrUnknownValue, err)
+	}
+
+	dir := t.TempDir()
+	if _, err := file.WriteFile(filepath.Join(dir, "main.tf")); !errors.Is(err, tokens.ErrUnknownValue) {
+		t.Errorf("expected %v, got %v", tokens.ErrUnknownValue, err)
+	}
+
+	project := tfsig.NewProjectSignature(nil)
+	project.AppendBlockTo("main.tf", sig)
+
+	_, _, err := project.Write(tfsig.NewDirFS(dir), tfsig.ProjectWriteOptions{IsGenerated: nil})
+	if !errors.Is(err, tokens.ErrUnknownValue) {
+		t.Errorf("expected %v, got %v", tokens.ErrUnknownValue, err)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected no file to be written, got %d", len(entries))
+	}
+
+	valid := tfsig.NewSignature("valid")
+	if block, buildErr := valid.SafeBuild(); buildErr != nil || block == nil {
+		t.Errorf("expected a block, got %v (error: %v)", block, buildErr)
+	}
+}
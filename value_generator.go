@@ -1,8 +1,8 @@
 package tfsig
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -13,7 +13,55 @@ import (
 // Capsule will then be converted to `hclwrite.tokens`
 // It allows to write values like `var.my_var`, `locals.my_local` or `data.res_name.val_name` without any quotes.
 type ValueGenerator struct {
-	matcher IdentTokenMatcherInterface
+	matcher         IdentTokenMatcherInterface
+	identKeys       bool
+	parsingMode     ParsingMode
+	extendedNumbers bool
+	keyOrder        []string
+}
+
+// ValueGeneratorOption is a functional option used to configure a ValueGenerator.
+type ValueGeneratorOption func(g *ValueGenerator)
+
+// WithIdentKeys enables 'ident' token detection for map and object keys
+//
+// Keys detected as 'ident' tokens are rendered wrapped into parentheses (e.g. `(var.key) = "value"`).
+func WithIdentKeys() ValueGeneratorOption {
+	return func(g *ValueGenerator) {
+		g.identKeys = true
+	}
+}
+
+// WithParsingMode configures how boolean strings are parsed (see ParsingMode).
+func WithParsingMode(mode ParsingMode) ValueGeneratorOption {
+	return func(g *ValueGenerator) {
+		g.parsingMode = mode
+	}
+}
+
+// WithExtendedNumbers enables hexadecimal (`0x1F`), octal (`0o17`) and binary (`0b101`) prefixes
+// as well as underscores between digits (`1_000`) when parsing numbers (only decimal values are accepted otherwise).
+func WithExtendedNumbers() ValueGeneratorOption {
+	return func(g *ValueGenerator) {
+		g.extendedNumbers = true
+	}
+}
+
+// WithMapKeyOrder renders maps and objects converted from Go maps as ordered objects (see `tokens.OrderedObject`):
+// provided keys come first, in the provided order, then remaining keys are sorted
+//
+// E.g. with `WithMapKeyOrder("Name")`, `{Env: "prod", Name: "app"}` is rendered as `{ Name = "app", Env = "prod" }`.
+// Calling it without any key leaves maps and objects rendering unchanged.
+func WithMapKeyOrder(keys ...string) ValueGeneratorOption {
+	return func(generator *ValueGenerator) {
+		if len(keys) == 0 {
+			generator.keyOrder = nil
+
+			return
+		}
+
+		generator.keyOrder = append([]string{}, keys...)
+	}
 }
 
 // NewValueGenerator returns a new ValueGenerator with the default 'ident' tokens matcher augmented with provided list
@@ -22,27 +70,40 @@ func NewValueGenerator(identPrefixList ...string) ValueGenerator {
 	return NewValueGeneratorWith(NewIdentTokenMatcher(identPrefixList...))
 }
 
-// NewValueGeneratorWith returns a new ValueGenerator with the provided matcher.
-func NewValueGeneratorWith(matcher IdentTokenMatcherInterface) ValueGenerator {
-	return ValueGenerator{matcher: matcher}
+// NewValueGeneratorWith returns a new ValueGenerator with the provided matcher and options.
+func NewValueGeneratorWith(matcher IdentTokenMatcherInterface, opts ...ValueGeneratorOption) ValueGenerator {
+	gen := ValueGenerator{
+		matcher:         matcher,
+		identKeys:       false,
+		parsingMode:     LenientParsing,
+		extendedNumbers: false,
+		keyOrder:        nil,
+	}
+	for _, opt := range opts {
+		opt(&gen)
+	}
+
+	return gen
 }
 
-// ToIdent converts a string to a special `cty.Value` capsule holding `hclwrite.tokens`.
+// ToIdent converts a string to a special `cty.Value` capsule holding `hclwrite.tokens`
+// String is tokenized as an HCL expression when possible (see `tokens.NewExpressionValue()`).
 func (g *ValueGenerator) ToIdent(s *string) *cty.Value {
 	if s == nil {
 		return nil
 	}
 
-	return tokens.NewIdentValue(*s)
+	return tokens.NewExpressionValue(*s)
 }
 
-// ToIdentList converts a list of string to `cty.Value` list containing capsules holding `hclwrite.tokens`.
+// ToIdentList converts a list of string to `cty.Value` list containing capsules holding `hclwrite.tokens`
+// Each string is tokenized as an HCL expression when possible (see `tokens.NewExpressionListValue()`).
 func (g *ValueGenerator) ToIdentList(list *[]string) *cty.Value {
 	if list == nil {
 		return nil
 	}
 
-	return tokens.NewIdentListValue(*list)
+	return tokens.NewExpressionListValue(*list)
 }
 
 // ToString convert a string to `cty.Value` string which will be rendered as quoted string by terraform HCL
@@ -90,30 +151,58 @@ func (g *ValueGenerator) ToStringList(list *[]string) *cty.Value {
 }
 
 // FromString convert a string to `cty.Value` of the provided type
-// If the provided string is actually an 'ident' token, `cty.Value` will be a capsule holding `hclwrite.tokens`.
+// If the provided string is actually an 'ident' token, `cty.Value` will be a capsule holding `hclwrite.tokens`
+// (token is first altered by the matcher if it implements IdentTokenExtractorInterface).
+//
+// It panics if the string can't be converted (see `ParseString()` for a version returning an error).
 func (g *ValueGenerator) FromString(val *string, toType cty.Type) *cty.Value {
+	ctyVal, err := g.ParseString(val, toType)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedType) {
+			panic(fmt.Sprintf("Unable to convert \"%s\" to a %s", *val, toType.FriendlyName()))
+		}
+
+		panic(err.Error())
+	}
+
+	return ctyVal
+}
+
+// ParseString convert a string to `cty.Value` of the provided type, like `FromString()` but returning an error
+// instead of panicking
+//
+// Booleans are parsed based on the configured ParsingMode (see `WithParsingMode()`).
+// Numbers are parsed as decimal values, unless extended syntax is enabled (see `WithExtendedNumbers()`).
+func (g *ValueGenerator) ParseString(val *string, toType cty.Type) (*cty.Value, error) {
 	if val == nil {
-		return nil
+		return nil, nil //nolint:nilnil // Nil in, nil out
 	}
 
 	if g.matcher.IsIdentToken(*val) {
-		return g.ToIdent(val)
+		token := extractIdentToken(g.matcher, *val)
+
+		return g.ToIdent(&token), nil
 	}
 
+	var (
+		ctyVal cty.Value
+		err    error
+	)
+
 	switch toType {
 	case cty.String:
-		val := cty.StringVal(*val)
-
-		return &val
+		ctyVal = cty.StringVal(*val)
 	case cty.Bool:
-		val := cty.BoolVal(*val == "1" || strings.ToLower(*val) == "true")
-
-		return &val
+		ctyVal, err = parseBool(*val, g.parsingMode)
 	case cty.Number:
-		val := cty.MustParseNumberVal(*val)
-
-		return &val
+		ctyVal, err = parseNumber(*val, g.extendedNumbers)
 	default:
-		panic(fmt.Sprintf("Unable to convert \"%s\" to a %s", *val, toType.FriendlyName()))
+		err = fmt.Errorf("%w: unable to convert %q to a %s", ErrUnsupportedType, *val, toType.FriendlyName())
+	}
+
+	if err != nil {
+		return nil, err
 	}
+
+	return &ctyVal, nil
 }
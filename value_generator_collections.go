@@ -0,0 +1,216 @@
+package tfsig
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+// ToNumberList converts a string list to `cty.Value` number list which will be rendered as numeric value list
+// by terraform HCL (see `FromStrings()`).
+func (g *ValueGenerator) ToNumberList(list *[]string) *cty.Value {
+	return g.FromStrings(list, cty.Number)
+}
+
+// ToBoolList converts a string list to `cty.Value` boolean list which will be rendered as true or false value list
+// by terraform HCL (see `FromStrings()`).
+func (g *ValueGenerator) ToBoolList(list *[]string) *cty.Value {
+	return g.FromStrings(list, cty.Bool)
+}
+
+// ToSet converts a string list to `cty.Value` set of the provided element type (duplicated values are removed).
+//
+// Like `FromStrings()`, it falls back on a tuple if an item is actually an 'ident' token.
+func (g *ValueGenerator) ToSet(list *[]string, elemType cty.Type) *cty.Value {
+	if list == nil {
+		return nil
+	}
+
+	values, hasCapsule := g.fromStrings(*list, elemType)
+
+	var val cty.Value
+
+	switch {
+	case hasCapsule:
+		val = cty.TupleVal(values)
+	case len(values) == 0:
+		val = cty.SetValEmpty(elemType)
+	default:
+		val = cty.SetVal(values)
+	}
+
+	return &val
+}
+
+// FromStrings converts a string list to `cty.Value` list of the provided element type, each item is converted
+// with `FromString()`.
+//
+// A homogeneous `cty.List` is returned if no item is an 'ident' token, a `cty.Tuple` otherwise.
+func (g *ValueGenerator) FromStrings(list *[]string, elemType cty.Type) *cty.Value {
+	if list == nil {
+		return nil
+	}
+
+	values, hasCapsule := g.fromStrings(*list, elemType)
+
+	var val cty.Value
+
+	switch {
+	case hasCapsule:
+		val = cty.TupleVal(values)
+	case len(values) == 0:
+		val = cty.ListValEmpty(elemType)
+	default:
+		val = cty.ListVal(values)
+	}
+
+	return &val
+}
+
+// ToStringMap converts a string map to `cty.Value` map which will be rendered as an object with quoted string values
+// by terraform HCL, keys are sorted (see also `WithMapKeyOrder()`).
+// If a provided string value is actually an 'ident' token, related `cty.Value` will be a capsule holding
+// `hclwrite.tokens`.
+func (g *ValueGenerator) ToStringMap(mapValue *map[string]string) *cty.Value {
+	if mapValue == nil {
+		return nil
+	}
+
+	keys := slices.Sorted(maps.Keys(*mapValue))
+	values := make(map[string]cty.Value, len(keys))
+
+	for _, key := range keys {
+		rawValue := (*mapValue)[key]
+		values[key] = *g.FromString(&rawValue, cty.String)
+	}
+
+	val := g.toMapValue(keys, values, cty.String)
+
+	return &val
+}
+
+// ToObject converts a map to `cty.Value` object which will be rendered as an object by terraform HCL, keys are sorted.
+//
+// Values are converted recursively with `FromGo()`, string values can therefore be rendered as 'ident' tokens.
+// Use `tokens.NewOrderedObjectValue()` to keep keys in insertion order.
+//
+// It panics if a value has an unsupported type.
+func (g *ValueGenerator) ToObject(mapValue *map[string]any) *cty.Value {
+	if mapValue == nil {
+		return nil
+	}
+
+	val, err := g.FromGo(*mapValue)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return &val
+}
+
+/** Private **/
+
+func (g *ValueGenerator) fromStrings(list []string, elemType cty.Type) ([]cty.Value, bool) {
+	values := make([]cty.Value, len(list))
+	hasCapsule := false
+
+	for idx := range list {
+		// Do not use `idx, rawValue := range ...` because of "G601: Implicit memory aliasing in for loop."
+		rawValue := list[idx]
+		values[idx] = *g.FromString(&rawValue, elemType)
+		hasCapsule = hasCapsule || tokens.IsCapsuleType(values[idx].Type())
+	}
+
+	return values, hasCapsule
+}
+
+// toObjectValue returns a cty object, or an ordered object if a key is an 'ident' token or if a key order
+// is configured.
+func (g *ValueGenerator) toObjectValue(keys []string, values map[string]cty.Value) cty.Value {
+	if g.keyOrder != nil || g.hasIdentKey(keys) {
+		return g.toOrderedObjectValue(g.orderKeys(keys), values)
+	}
+
+	return cty.ObjectVal(values)
+}
+
+// toMapValue returns a cty map if all values have the same type, else a cty object
+// (or an ordered object if a key is an 'ident' token or if a key order is configured).
+func (g *ValueGenerator) toMapValue(keys []string, values map[string]cty.Value, elemType cty.Type) cty.Value {
+	if g.keyOrder != nil || g.hasIdentKey(keys) {
+		return g.toOrderedObjectValue(g.orderKeys(keys), values)
+	}
+
+	if len(values) == 0 {
+		return cty.MapValEmpty(elemType)
+	}
+
+	for _, key := range keys[1:] {
+		if !values[key].Type().Equals(values[keys[0]].Type()) {
+			return cty.ObjectVal(values)
+		}
+	}
+
+	return cty.MapVal(values)
+}
+
+func (g *ValueGenerator) hasIdentKey(keys []string) bool {
+	if !g.identKeys {
+		return false
+	}
+
+	for _, key := range keys {
+		if g.matcher.IsIdentToken(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orderKeys returns keys listed with `WithMapKeyOrder()` first, then remaining keys in provided (sorted) order.
+func (g *ValueGenerator) orderKeys(sortedKeys []string) []string {
+	if len(g.keyOrder) == 0 {
+		return sortedKeys
+	}
+
+	present := make(map[string]bool, len(sortedKeys))
+	for _, key := range sortedKeys {
+		present[key] = true
+	}
+
+	ordered := make([]string, 0, len(sortedKeys))
+	listed := make(map[string]bool, len(g.keyOrder))
+
+	for _, key := range g.keyOrder {
+		if present[key] && !listed[key] {
+			ordered = append(ordered, key)
+			listed[key] = true
+		}
+	}
+
+	for _, key := range sortedKeys {
+		if !listed[key] {
+			ordered = append(ordered, key)
+		}
+	}
+
+	return ordered
+}
+
+func (g *ValueGenerator) toOrderedObjectValue(keys []string, values map[string]cty.Value) cty.Value {
+	entries := make([]tokens.ObjectEntry, len(keys))
+
+	for idx, key := range keys {
+		if g.identKeys && g.matcher.IsIdentToken(key) {
+			entries[idx] = tokens.NewExpressionObjectEntry(extractIdentToken(g.matcher, key), values[key])
+		} else {
+			entries[idx] = tokens.NewObjectEntry(key, values[key])
+		}
+	}
+
+	return tokens.NewOrderedObjectValue(entries...)
+}
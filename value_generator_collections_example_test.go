@@ -0,0 +1,40 @@
+package tfsig_test
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func ExampleValueGenerator_ToObject() {
+	tags := map[string]string{"Name": "var.name", "Env": "prod"}
+	config := map[string]any{
+		"enabled": true,
+		"ports":   []any{80, 443},
+		"owner":   "local.owner",
+	}
+
+	valGen := tfsig.NewValueGenerator()
+	sig := tfsig.NewResource("res_name", "res_id")
+	sig.AppendAttribute("tags", *valGen.ToStringMap(&tags))
+	sig.AppendAttribute("config", *valGen.ToObject(&config))
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(sig.Build())
+	fmt.Println(string(hclFile.Bytes()))
+
+	// Output:
+	// resource "res_name" "res_id" {
+	//   tags = {
+	//     "Env"  = "prod"
+	//     "Name" = var.name
+	//   }
+	//   config = {
+	//     "enabled" = true
+	//     "owner"   = local.owner
+	//     "ports"   = [80, 443]
+	//   }
+	// }
+}
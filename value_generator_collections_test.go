@@ -0,0 +1,206 @@
+package tfsig_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/testutils"
+)
+
+func TestValueGenerator_maps(t *testing.T) {
+	t.Parallel()
+
+	stringMap := map[string]string{"Name": "var.name", "Env": "prod", "my key": "value"}
+	sameTypeMap := map[string]string{"A": "a", "B": "b"}
+	emptyMap := map[string]string{}
+	identKeyMap := map[string]string{"var.key": "value", "Env": "prod"}
+	object := map[string]any{
+		"string":     "value",
+		"ident":      "local.foo",
+		"bool":       true,
+		"int":        1,
+		"int64":      int64(2),
+		"float":      1.5,
+		"null":       nil,
+		"cty":        cty.StringVal("cty"),
+		"string_map": map[string]string{"A": "data.a.b"},
+		"list":       []string{"A", "var.b"},
+		"tuple":      []any{"A", 1, map[string]any{"B": "var.b"}},
+		"empty":      []any{},
+	}
+
+	valGen := tfsig.NewValueGenerator()
+	identKeysValGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), tfsig.WithIdentKeys())
+	keyOrderValGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), tfsig.WithMapKeyOrder("Name", "Missing"))
+	keyOrderObject := map[string]any{"tags": stringMap, "Name": "app", "count": 1}
+
+	sig := tfsig.NewSignature("sig")
+	sig.AppendAttribute("string_map", *valGen.ToStringMap(&stringMap))
+	sig.AppendAttribute("same_type_map", *valGen.ToStringMap(&sameTypeMap))
+	sig.AppendAttribute("empty_map", *valGen.ToStringMap(&emptyMap))
+	sig.AppendAttribute("ident_key_map_disabled", *valGen.ToStringMap(&identKeyMap))
+	sig.AppendAttribute("ident_key_map", *identKeysValGen.ToStringMap(&identKeyMap))
+	sig.AppendAttribute("object", *valGen.ToObject(&object))
+	sig.AppendAttribute("key_order_map", *keyOrderValGen.ToStringMap(&stringMap))
+	sig.AppendAttribute("key_order_object", *keyOrderValGen.ToObject(&keyOrderObject))
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().AppendBlock(sig.Build())
+
+	expected := `sig {
+  string_map = {
+    "Env"    = "prod"
+    "Name"   = var.name
+    "my key" = "value"
+  }
+  same_type_map = {
+    A = "a"
+    B = "b"
+  }
+  empty_map = {}
+  ident_key_map_disabled = {
+    Env       = "prod"
+    "var.key" = "value"
+  }
+  ident_key_map = {
+    Env       = "prod"
+    (var.key) = "value"
+  }
+  object = {
+    "bool"   = true
+    "cty"    = "cty"
+    "empty"  = []
+    "float"  = 1.5
+    "ident"  = local.foo
+    "int"    = 1
+    "int64"  = 2
+    "list"   = ["A", var.b]
+    "null"   = null
+    "string" = "value"
+    "string_map" = {
+      "A" = data.a.b
+    }
+    "tuple" = ["A", 1, {
+      "B" = var.b
+    }]
+  }
+  key_order_map = {
+    Name     = var.name
+    Env      = "prod"
+    "my key" = "value"
+  }
+  key_order_object = {
+    Name  = "app"
+    count = 1
+    tags = {
+      Name     = var.name
+      Env      = "prod"
+      "my key" = "value"
+    }
+  }
+}
+`
+
+	if err := testutils.EnsureFileContentEquals(hclFile, expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValueGenerator_emptyMapKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	stringMap := map[string]string{"Name": "app", "Env": "prod"}
+	valGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), tfsig.WithMapKeyOrder())
+
+	if value := valGen.ToStringMap(&stringMap); !value.Type().IsMapType() {
+		t.Errorf("expected a map value, got %s", value.Type().FriendlyName())
+	}
+}
+
+func TestValueGenerator_maps_nil(t *testing.T) {
+	t.Parallel()
+
+	valGen := tfsig.NewValueGenerator()
+
+	if valGen.ToStringMap(nil) != nil || valGen.ToObject(nil) != nil {
+		t.Error("expected nil values")
+	}
+}
+
+func TestValueGenerator_ToObject_panic(t *testing.T) {
+	t.Parallel()
+
+	valGen := tfsig.NewValueGenerator()
+	object := map[string]any{"A": struct{}{}}
+
+	testutils.ExpectPanic(
+		t,
+		"Unsupported type",
+		func() {
+			valGen.ToObject(&object)
+		},
+		"unable to convert struct {} to a cty value: unsupported type",
+	)
+}
+
+func TestValueGenerator_lists(t *testing.T) {
+	t.Parallel()
+
+	numbers := []string{"80", "443", "8080"}
+	numbersWithIdent := []string{"80", "var.port"}
+	bools := []string{"true", "false", "1"}
+	duplicated := []string{"a", "b", "a"}
+	empty := []string{}
+
+	valGen := tfsig.NewValueGenerator()
+
+	cases := map[string]struct {
+		value        *cty.Value
+		expectedType cty.Type
+		expected     string
+	}{
+		"Number list":            {valGen.ToNumberList(&numbers), cty.List(cty.Number), "[80, 443, 8080]"},
+		"Bool list":              {valGen.ToBoolList(&bools), cty.List(cty.Bool), "[true, false, true]"},
+		"Empty list":             {valGen.FromStrings(&empty, cty.String), cty.List(cty.String), "[]"},
+		"Set":                    {valGen.ToSet(&duplicated, cty.String), cty.Set(cty.String), `["a", "b"]`},
+		"Empty set":              {valGen.ToSet(&empty, cty.Number), cty.Set(cty.Number), "[]"},
+		"Number list with ident": {valGen.ToNumberList(&numbersWithIdent), cty.NilType, "[80, var.port]"},
+		"Set with ident":         {valGen.ToSet(&numbersWithIdent, cty.Number), cty.NilType, "[80, var.port]"},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				valType := tcase.value.Type()
+				if tcase.expectedType == cty.NilType && !valType.IsTupleType() {
+					t.Errorf("Case \"%s\": expected a tuple, got %s", t.Name(), valType.GoString())
+				} else if tcase.expectedType != cty.NilType && !valType.Equals(tcase.expectedType) {
+					t.Errorf("Case \"%s\": expected %s, got %s", t.Name(), tcase.expectedType.GoString(), valType.GoString())
+				}
+
+				file := tfsig.NewFileSignature()
+				file.AppendAttribute("attr", *tcase.value)
+
+				if err := testutils.EnsureFileContentEquals(file.Build(), "attr = "+tcase.expected+"\n"); err != nil {
+					t.Errorf("Case \"%s\": %v", t.Name(), err)
+				}
+			},
+		)
+	}
+}
+
+func TestValueGenerator_lists_nil(t *testing.T) {
+	t.Parallel()
+
+	valGen := tfsig.NewValueGenerator()
+
+	if valGen.ToNumberList(nil) != nil || valGen.ToBoolList(nil) != nil || valGen.ToSet(nil, cty.String) != nil {
+		t.Error("expected nil values")
+	}
+}
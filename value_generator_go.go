@@ -0,0 +1,163 @@
+package tfsig
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ErrUnsupportedType is returned by `ValueGenerator.FromGo()` when a value can't be converted to a `cty.Value`.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// FromGo converts a Go value (e.g. decoded YAML or JSON document) to `cty.Value`, inferring cty types recursively
+//
+// - nil values (including nil pointers) are converted to `null`
+// - strings are converted with `ToString()` and therefore can be rendered as 'ident' tokens
+// - booleans, integers, floats and `json.Number` are converted to bool and number values
+// - `time.Time` values are converted to RFC3339 strings and `encoding.TextMarshaler` values to strings
+// - slices and arrays are converted to tuples
+// - maps with string keys are converted to objects (keys are sorted), except `map[string]string` which is converted
+// with `ToStringMap()`
+// - `cty.Value` are kept as is (e.g. `tokens.NewOrderedObjectValue()` to preserve keys order)
+//
+// It returns an `ErrUnsupportedType` error for any other type (e.g. structs, channels or maps with non-string keys)
+// and for NaN or infinite floats, which can't be represented in HCL.
+func (g *ValueGenerator) FromGo(value any) (cty.Value, error) {
+	// Dereference pointers first, so that e.g. `*time.Time` is handled like `time.Time`
+	if refValue := reflect.ValueOf(value); refValue.Kind() == reflect.Pointer {
+		if refValue.IsNil() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+
+		return g.FromGo(refValue.Elem().Interface())
+	}
+
+	return g.fromGoValue(value)
+}
+
+/** Private **/
+
+func (g *ValueGenerator) fromGoValue(value any) (cty.Value, error) {
+	switch typed := value.(type) {
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case cty.Value:
+		return typed, nil
+	case string:
+		return *g.ToString(&typed), nil
+	case map[string]string:
+		return *g.ToStringMap(&typed), nil
+	case json.Number:
+		return fromJSONNumber(typed)
+	case time.Time:
+		return cty.StringVal(typed.Format(time.RFC3339)), nil
+	case encoding.TextMarshaler:
+		return g.fromTextMarshaler(typed)
+	}
+
+	return g.fromReflectValue(reflect.ValueOf(value))
+}
+
+//nolint:exhaustive // Other kinds are not supported
+func (g *ValueGenerator) fromReflectValue(refValue reflect.Value) (cty.Value, error) {
+	switch refValue.Kind() {
+	case reflect.Interface:
+		if refValue.IsNil() {
+			return cty.NullVal(cty.DynamicPseudoType), nil
+		}
+
+		return g.FromGo(refValue.Elem().Interface())
+	case reflect.String:
+		str := refValue.String()
+
+		return *g.ToString(&str), nil
+	case reflect.Bool:
+		return cty.BoolVal(refValue.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cty.NumberIntVal(refValue.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cty.NumberUIntVal(refValue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return fromGoFloat(refValue.Float())
+	case reflect.Slice, reflect.Array:
+		return g.fromGoList(refValue)
+	case reflect.Map:
+		return g.fromGoMap(refValue)
+	}
+
+	return cty.NilVal, fmt.Errorf("unable to convert %s to a cty value: %w", refValue.Type(), ErrUnsupportedType)
+}
+
+func (g *ValueGenerator) fromGoList(refValue reflect.Value) (cty.Value, error) {
+	if refValue.Len() == 0 {
+		return cty.EmptyTupleVal, nil
+	}
+
+	values := make([]cty.Value, refValue.Len())
+
+	for idx := range refValue.Len() {
+		val, err := g.FromGo(refValue.Index(idx).Interface())
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		values[idx] = val
+	}
+
+	return cty.TupleVal(values), nil
+}
+
+func (g *ValueGenerator) fromGoMap(refValue reflect.Value) (cty.Value, error) {
+	if refValue.Type().Key().Kind() != reflect.String {
+		return cty.NilVal, fmt.Errorf("unable to convert %s to a cty value: %w", refValue.Type(), ErrUnsupportedType)
+	}
+
+	values := make(map[string]cty.Value, refValue.Len())
+
+	for iter := refValue.MapRange(); iter.Next(); {
+		val, err := g.FromGo(iter.Value().Interface())
+		if err != nil {
+			return cty.NilVal, err
+		}
+
+		values[iter.Key().String()] = val
+	}
+
+	return g.toObjectValue(slices.Sorted(maps.Keys(values)), values), nil
+}
+
+func (g *ValueGenerator) fromTextMarshaler(value encoding.TextMarshaler) (cty.Value, error) {
+	text, err := value.MarshalText()
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("unable to marshal %T: %w", value, err)
+	}
+
+	textValue := string(text)
+
+	return *g.ToString(&textValue), nil
+}
+
+func fromJSONNumber(value json.Number) (cty.Value, error) {
+	val, err := cty.ParseNumberVal(value.String())
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("unable to convert %q to a number: %w", value.String(), err)
+	}
+
+	return val, nil
+}
+
+func fromGoFloat(value float64) (cty.Value, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return cty.NilVal, fmt.Errorf("unable to convert %v to a cty value: %w", value, ErrUnsupportedType)
+	}
+
+	return cty.NumberFloatVal(value), nil
+}
@@ -0,0 +1,155 @@
+package tfsig_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+type errTextMarshaler struct{}
+
+var errMarshalText = errors.New("marshal error")
+
+func (errTextMarshaler) MarshalText() ([]byte, error) {
+	return nil, errMarshalText
+}
+
+func TestValueGenerator_FromGo(t *testing.T) {
+	t.Parallel()
+
+	var (
+		nilPointer *string
+		nilTime    *time.Time
+		str        = "var.foo"
+		moment     = time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	)
+
+	var decoded any
+	if err := json.Unmarshal(
+		[]byte(`{"name": "var.name", "ports": [80, 443], "nested": {"enabled": true, "ratio": 0.5, "none": null}}`),
+		&decoded,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		value    any
+		expected string
+	}{
+		"Nil":            {nil, "null"},
+		"Nil pointer":    {nilPointer, "null"},
+		"Pointer":        {&str, "var.foo"},
+		"String":         {"value", `"value"`},
+		"Ident":          {"local.foo", "local.foo"},
+		"Bool":           {true, "true"},
+		"Int":            {int8(-3), "-3"},
+		"Uint":           {uint16(3), "3"},
+		"Float":          {float32(1.5), "1.5"},
+		"JSON number":    {json.Number("12345678901234567890"), "12345678901234567890"},
+		"Time":           {time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), `"2024-01-02T03:04:05Z"`},
+		"Nil time ptr":   {nilTime, "null"},
+		"Time pointer":   {&moment, `"2024-01-02T03:04:05Z"`},
+		"Text marshaler": {net.ParseIP("10.0.0.1"), `"10.0.0.1"`},
+		"Array":          {[2]int{1, 2}, "[1, 2]"},
+		"Empty slice":    {[]any{}, "[]"},
+		"Cty value":      {cty.NumberIntVal(1), "1"},
+		"Map of int":     {map[string]int{"B": 2, "A": 1}, "{\n  A = 1\n  B = 2\n}"},
+		"Map of string":  {map[string]string{"A": "var.a"}, "{\n  \"A\" = var.a\n}"},
+		"Ordered object": {
+			tokens.NewOrderedObjectValue(
+				tokens.NewObjectEntry("B", cty.NumberIntVal(2)),
+				tokens.NewObjectEntry("A", cty.NumberIntVal(1)),
+			),
+			"{\n  B = 2\n  A = 1\n}",
+		},
+		"Decoded JSON doc": {
+			decoded,
+			`{
+  "name" = var.name
+  "nested" = {
+    enabled = true
+    none    = null
+    ratio   = 0.5
+  }
+  "ports" = [80, 443]
+}`,
+		},
+	}
+
+	valGen := tfsig.NewValueGenerator()
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				val, err := valGen.FromGo(tcase.value)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", t.Name(), err)
+				}
+
+				file := tfsig.NewFileSignature()
+				file.AppendAttribute("attr", val)
+
+				if err = testutils.EnsureFileContentEquals(file.Build(), "attr = "+tcase.expected+"\n"); err != nil {
+					t.Errorf("Case \"%s\": %v", t.Name(), err)
+				}
+			},
+		)
+	}
+}
+
+func TestValueGenerator_FromGo_error(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value         any
+		expectedError error
+		expectedMsg   string
+	}{
+		"Struct":             {struct{}{}, tfsig.ErrUnsupportedType, "unable to convert struct {} to a cty value"},
+		"Map with int keys":  {map[int]string{}, tfsig.ErrUnsupportedType, "unable to convert map[int]string"},
+		"Nested in list":     {[]any{make(chan int)}, tfsig.ErrUnsupportedType, "unable to convert chan int"},
+		"Nested in map":      {map[string]any{"A": struct{}{}}, tfsig.ErrUnsupportedType, "unable to convert struct {}"},
+		"Invalid JSON num":   {json.Number("abc"), nil, `unable to convert "abc" to a number`},
+		"Text marshal error": {errTextMarshaler{}, errMarshalText, "unable to marshal tfsig_test.errTextMarshaler"},
+		"NaN":                {math.NaN(), tfsig.ErrUnsupportedType, "unable to convert NaN to a cty value"},
+		"Positive infinity":  {math.Inf(1), tfsig.ErrUnsupportedType, "unable to convert +Inf to a cty value"},
+		"Negative infinity":  {float32(math.Inf(-1)), tfsig.ErrUnsupportedType, "unable to convert -Inf"},
+	}
+
+	valGen := tfsig.NewValueGenerator()
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				_, err := valGen.FromGo(tcase.value)
+				if err == nil {
+					t.Fatalf("Case \"%s\": expected an error", t.Name())
+				}
+
+				if tcase.expectedError != nil && !errors.Is(err, tcase.expectedError) {
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expectedError, err)
+				}
+
+				if !strings.Contains(err.Error(), tcase.expectedMsg) {
+					t.Errorf("Case \"%s\": expected message to contain %q, got %q", t.Name(), tcase.expectedMsg, err)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,98 @@
+package tfsig
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ErrInvalidBool is returned when a string can't be parsed as a boolean in StrictParsing mode.
+var ErrInvalidBool = errors.New("invalid boolean")
+
+// ErrInvalidNumber is returned when a string can't be parsed as a number.
+var ErrInvalidNumber = errors.New("invalid number")
+
+// ParsingMode defines how ValueGenerator parses boolean strings.
+type ParsingMode int
+
+const (
+	// LenientParsing considers `1` and `true` (case-insensitive) as true and anything else as false (default).
+	LenientParsing ParsingMode = iota
+	// StrictParsing accepts only `true`, `false`, `1`, `0`, `yes` and `no` (case-insensitive) and returns
+	// an `ErrInvalidBool` error otherwise.
+	StrictParsing
+)
+
+/** Private **/
+
+const trueString = "true"
+
+func parseBool(str string, mode ParsingMode) (cty.Value, error) {
+	lowered := strings.ToLower(strings.TrimSpace(str))
+
+	if mode == StrictParsing {
+		switch lowered {
+		case trueString, "1", "yes":
+			return cty.True, nil
+		case "false", "0", "no":
+			return cty.False, nil
+		default:
+			return cty.NilVal, fmt.Errorf("%w: %q", ErrInvalidBool, str)
+		}
+	}
+
+	return cty.BoolVal(str == "1" || strings.ToLower(str) == trueString), nil
+}
+
+func parseNumber(str string, extended bool) (cty.Value, error) {
+	trimmed := strings.TrimSpace(str)
+
+	if extended {
+		if hasBasePrefix(trimmed) {
+			// Integers with base prefix and optional underscores (arbitrary precision)
+			if intVal, ok := new(big.Int).SetString(trimmed, 0); ok {
+				return cty.NumberVal(new(big.Float).SetInt(intVal)), nil
+			}
+
+			return cty.NilVal, fmt.Errorf("%w: %q", ErrInvalidNumber, str)
+		}
+
+		if !hasValidUnderscores(trimmed) {
+			return cty.NilVal, fmt.Errorf("%w: %q", ErrInvalidNumber, str)
+		}
+
+		trimmed = strings.ReplaceAll(trimmed, "_", "")
+	}
+
+	// Decimal values (arbitrary precision)
+	if val, err := cty.ParseNumberVal(trimmed); err == nil && !val.AsBigFloat().IsInf() {
+		return val, nil
+	}
+
+	return cty.NilVal, fmt.Errorf("%w: %q", ErrInvalidNumber, str)
+}
+
+// hasBasePrefix returns true if the number starts with `0x`, `0o` or `0b` (case-insensitive), sign excluded.
+func hasBasePrefix(str string) bool {
+	unsigned := strings.ToLower(strings.TrimLeft(str, "+-"))
+
+	return strings.HasPrefix(unsigned, "0x") || strings.HasPrefix(unsigned, "0o") || strings.HasPrefix(unsigned, "0b")
+}
+
+// hasValidUnderscores returns true if each underscore is located between two digits.
+func hasValidUnderscores(str string) bool {
+	for idx, char := range str {
+		if char == '_' && (idx == 0 || idx == len(str)-1 || !isDigit(str[idx-1]) || !isDigit(str[idx+1])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
@@ -0,0 +1,151 @@
+package tfsig_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+)
+
+func TestValueGenerator_ParseString(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		toType   cty.Type
+		mode     tfsig.ParsingMode
+		expected cty.Value
+	}{
+		"Lenient true":    {"TRUE", cty.Bool, tfsig.LenientParsing, cty.True},
+		"Lenient 1":       {"1", cty.Bool, tfsig.LenientParsing, cty.True},
+		"Lenient garbage": {"yes please", cty.Bool, tfsig.LenientParsing, cty.False},
+		"Strict yes":      {"Yes", cty.Bool, tfsig.StrictParsing, cty.True},
+		"Strict no":       {"no", cty.Bool, tfsig.StrictParsing, cty.False},
+		"Strict 0":        {" 0 ", cty.Bool, tfsig.StrictParsing, cty.False},
+		"Decimal":         {"12.5", cty.Number, tfsig.StrictParsing, cty.NumberFloatVal(12.5)},
+		"String":          {"0x1F", cty.String, tfsig.StrictParsing, cty.StringVal("0x1F")},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				valGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), tfsig.WithParsingMode(tcase.mode))
+
+				val, err := valGen.ParseString(&tcase.value, tcase.toType)
+				if err != nil {
+					t.Fatalf("Case \"%s\": unexpected error %v", t.Name(), err)
+				}
+
+				if !val.RawEquals(tcase.expected) {
+					t.Errorf("Case \"%s\": expected %#v, got %#v", t.Name(), tcase.expected, *val)
+				}
+			},
+		)
+	}
+}
+
+func TestValueGenerator_ParseString_number(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value    string
+		extended bool
+		expected cty.Value
+	}{
+		"Zero & underscores":           {"0_10", false, cty.NilVal},
+		"Leading zero":                 {"010", false, cty.NumberIntVal(10)},
+		"Hexadecimal":                  {"0x1F", false, cty.NilVal},
+		"Underscores":                  {"1_000", false, cty.NilVal},
+		"Extended leading zero":        {"010", true, cty.NumberIntVal(10)},
+		"Extended zero & underscores":  {"0_10", true, cty.NumberIntVal(10)},
+		"Extended hexadecimal":         {"0x1F", true, cty.NumberIntVal(31)},
+		"Extended negative hex":        {"-0x1F", true, cty.NumberIntVal(-31)},
+		"Extended octal":               {"0o17", true, cty.NumberIntVal(15)},
+		"Extended binary":              {"0b101", true, cty.NumberIntVal(5)},
+		"Extended underscores":         {"1_000", true, cty.NumberIntVal(1000)},
+		"Extended float & underscores": {"1_000.5", true, cty.NumberFloatVal(1000.5)},
+		"Extended misplaced _":         {"1__0", true, cty.NilVal},
+		"Extended leading _":           {"_10", true, cty.NilVal},
+		"Extended _ before dot":        {"1_.5", true, cty.NilVal},
+		"Extended invalid hex":         {"0x1G", true, cty.NilVal},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				opts := []tfsig.ValueGeneratorOption{}
+				if tcase.extended {
+					opts = append(opts, tfsig.WithExtendedNumbers())
+				}
+
+				valGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), opts...)
+
+				val, err := valGen.ParseString(&tcase.value, cty.Number)
+
+				switch {
+				case tcase.expected == cty.NilVal && !errors.Is(err, tfsig.ErrInvalidNumber):
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tfsig.ErrInvalidNumber, err)
+				case tcase.expected != cty.NilVal && err != nil:
+					t.Errorf("Case \"%s\": unexpected error %v", t.Name(), err)
+				case tcase.expected != cty.NilVal && !val.Equals(tcase.expected).True():
+					t.Errorf("Case \"%s\": expected %#v, got %#v", t.Name(), tcase.expected, *val)
+				}
+			},
+		)
+	}
+}
+
+func TestValueGenerator_ParseString_error(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value         string
+		toType        cty.Type
+		expectedError error
+	}{
+		"Strict bool":      {"yes please", cty.Bool, tfsig.ErrInvalidBool},
+		"Empty bool":       {"", cty.Bool, tfsig.ErrInvalidBool},
+		"Number":           {"12abc", cty.Number, tfsig.ErrInvalidNumber},
+		"Misplaced _":      {"1__0", cty.Number, tfsig.ErrInvalidNumber},
+		"Infinity":         {"Inf", cty.Number, tfsig.ErrInvalidNumber},
+		"Unsupported type": {"a_value", cty.Map(cty.String), tfsig.ErrUnsupportedType},
+	}
+
+	valGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), tfsig.WithParsingMode(tfsig.StrictParsing))
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				_, err := valGen.ParseString(&tcase.value, tcase.toType)
+				if !errors.Is(err, tcase.expectedError) {
+					t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), tcase.expectedError, err)
+				}
+			},
+		)
+	}
+}
+
+func TestValueGenerator_FromString_strictPanic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r != `invalid boolean: "maybe"` {
+			t.Errorf("unexpected panic: %v", r)
+		}
+	}()
+
+	value := "maybe"
+	valGen := tfsig.NewValueGeneratorWith(tfsig.NewIdentTokenMatcher(), tfsig.WithParsingMode(tfsig.StrictParsing))
+	valGen.ToBool(&value)
+}
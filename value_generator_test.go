@@ -8,6 +8,7 @@ import (
 
 	"github.com/yoanm/go-tfsig"
 	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
 )
 
 func TestNewValueGenerator(t *testing.T) {
@@ -147,3 +148,17 @@ func TestToIdentList_nil(t *testing.T) {
 		t.Errorf("wrong result: expected nil, got %v", actual)
 	}
 }
+
+func TestToIdentList_expressions(t *testing.T) {
+	t.Parallel()
+
+	valGen := tfsig.NewValueGenerator()
+	list := []string{"data.x.y[0].id", "var.x is cool"}
+
+	actual := valGen.ToIdentList(&list).AsValueSlice()
+	for idx, expected := range []int{10, 1} {
+		if count := len(tokens.FromValue(actual[idx])); count != expected {
+			t.Errorf("Case %q: expected %d tokens, got %d", list[idx], expected, count)
+		}
+	}
+}
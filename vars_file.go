@@ -0,0 +1,262 @@
+package tfsig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+const (
+	variableBlockType        = "variable"
+	variableTypeAttribute    = "type"
+	variableDefaultAttribute = "default"
+)
+
+var (
+	// ErrUnknownVariable is returned by `VarsFile.Validate()` when a value is set for a variable which is not declared.
+	ErrUnknownVariable = errors.New("unknown variable")
+	// ErrMissingVariable is returned by `VarsFile.Validate()` when a variable without default value is not set.
+	ErrMissingVariable = errors.New("missing required variable")
+	// ErrInvalidVariableValue is returned by `VarsFile.Validate()` when a value doesn't match the type constraint
+	// of the variable, or is not a literal value.
+	ErrInvalidVariableValue = errors.New("invalid variable value")
+	// ErrInvalidVariable is returned by `VarsFile.Validate()` when the type constraint of a variable is not valid.
+	ErrInvalidVariable = errors.New("invalid variable definition")
+)
+
+// NewVarsFile returns a VarsFile pointer using the provided ValueGenerator to convert Go values (see `SetGo()`).
+func NewVarsFile(generator ValueGenerator) *VarsFile {
+	return &VarsFile{generator: generator, file: NewFileSignature()}
+}
+
+// VarsFile is a wrapper to a `.tfvars` file, it holds top-level attributes only (no blocks).
+type VarsFile struct {
+	generator ValueGenerator
+	file      *FileSignature
+}
+
+// Set sets the value of the provided variable, an existing value is replaced in place.
+func (v *VarsFile) Set(name string, value cty.Value) {
+	elements := v.file.GetElements()
+	for idx, elem := range elements {
+		if elem.IsBodyAttribute() && elem.GetName() == name {
+			elements[idx] = NewBodyAttribute(name, value, elem.GetFormatOptions()...)
+
+			return
+		}
+	}
+
+	v.file.AppendAttribute(name, value)
+}
+
+// SetGo converts the provided Go value with `ValueGenerator.FromGo()` and sets it as value of the provided variable.
+func (v *VarsFile) SetGo(name string, value any) error {
+	val, err := v.generator.FromGo(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	v.Set(name, val)
+
+	return nil
+}
+
+// Get returns the value of the provided variable and whether it is set.
+func (v *VarsFile) Get(name string) (cty.Value, bool) {
+	for _, elem := range v.file.GetElements() {
+		if elem.IsBodyAttribute() && elem.GetName() == name {
+			return *elem.GetBodyAttribute(), true
+		}
+	}
+
+	return cty.NilVal, false
+}
+
+// GetNames returns names of variables set, in insertion order.
+func (v *VarsFile) GetNames() []string {
+	names := []string{}
+
+	for _, elem := range v.file.GetElements() {
+		if elem.IsBodyAttribute() {
+			names = append(names, elem.GetName())
+		}
+	}
+
+	return names
+}
+
+// Build creates a `hclwrite.File` containing all variable values.
+func (v *VarsFile) Build() *hclwrite.File {
+	return v.file.Build()
+}
+
+// BuildRedacted creates a `hclwrite.File` like `Build()`, except that values marked as Sensitive
+// are rendered as `"(sensitive)"` placeholders.
+func (v *VarsFile) BuildRedacted() *hclwrite.File {
+	return v.file.BuildRedacted()
+}
+
+// Bytes returns the content of the file.
+func (v *VarsFile) Bytes() []byte {
+	return v.file.Bytes()
+}
+
+// Validate checks values against provided `variable` block signatures (other blocks are ignored):
+//
+// - each value must match the type constraint of the related variable (`type` attribute, `any` if not defined)
+// - each value must be a literal value (expressions like `var.foo` are not allowed in `.tfvars` files)
+// - each variable without `default` attribute must be set
+// - each value must be related to a declared variable
+//
+// It returns all errors joined (see `ErrUnknownVariable`, `ErrMissingVariable`, `ErrInvalidVariableValue`
+// and `ErrInvalidVariable`).
+func (v *VarsFile) Validate(variables ...*BlockSignature) error {
+	errs := []error{}
+	declared, order := declaredVariables(variables)
+
+	for _, name := range v.GetNames() {
+		variable, exists := declared[name]
+		if !exists {
+			errs = append(errs, fmt.Errorf("%w %q", ErrUnknownVariable, name))
+
+			continue
+		}
+
+		value, _ := v.Get(name)
+		if err := validateVariableValue(name, variable, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, name := range order {
+		if _, isSet := v.Get(name); !isSet && findAttribute(declared[name], variableDefaultAttribute) == nil {
+			errs = append(errs, fmt.Errorf("%w %q", ErrMissingVariable, name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WriteFile validates values against provided `variable` block signatures if any (see `Validate()`),
+// then writes the content to the provided path (see `FileSignature.WriteFile()`).
+//
+// It returns true if the file has been written.
+func (v *VarsFile) WriteFile(path string, variables ...*BlockSignature) (bool, error) {
+	if len(variables) > 0 {
+		if err := v.Validate(variables...); err != nil {
+			return false, err
+		}
+	}
+
+	return v.file.WriteFile(path)
+}
+
+/** Private **/
+
+func validateVariableValue(name string, variable *BlockSignature, value cty.Value) error {
+	varType, defaults, err := variableTypeConstraint(variable)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidVariable, name, err)
+	}
+
+	literal, err := literalValue(value)
+	if err != nil {
+		return fmt.Errorf("%w for %q: %w", ErrInvalidVariableValue, name, err)
+	}
+
+	if defaults != nil {
+		literal = defaults.Apply(literal)
+	}
+
+	if _, err = convert.Convert(literal, varType); err != nil {
+		return fmt.Errorf(
+			"%w for %q: expected %s: %w",
+			ErrInvalidVariableValue,
+			name,
+			typeexpr.TypeString(varType),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// declaredVariables returns `variable` block signatures indexed by name, alongside names in declaration order.
+func declaredVariables(variables []*BlockSignature) (map[string]*BlockSignature, []string) {
+	declared := map[string]*BlockSignature{}
+	order := []string{}
+
+	for _, variable := range variables {
+		if variable == nil || variable.GetType() != variableBlockType || len(variable.GetLabels()) != 1 {
+			continue
+		}
+
+		name := variable.GetLabels()[0]
+		if _, exists := declared[name]; !exists {
+			order = append(order, name)
+		}
+
+		declared[name] = variable
+	}
+
+	return declared, order
+}
+
+// variableTypeConstraint returns the type constraint of the variable (`any` if `type` attribute is not defined).
+func variableTypeConstraint(variable *BlockSignature) (cty.Type, *typeexpr.Defaults, error) {
+	typeValue := findAttribute(variable, variableTypeAttribute)
+	if typeValue == nil {
+		return cty.DynamicPseudoType, nil, nil
+	}
+
+	expr, diags := hclsyntax.ParseExpression(hclwrite.Format(tokens.Generate(typeValue).Bytes()), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, nil, diags
+	}
+
+	varType, defaults, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.NilType, nil, diags
+	}
+
+	return varType, defaults, nil
+}
+
+// literalValue returns the unmarked value, values containing special capsules are evaluated without any variable
+// or function, so that only literal values are accepted.
+func literalValue(value cty.Value) (cty.Value, error) {
+	unmarked, _ := value.UnmarkDeep()
+	if !tokens.ContainsCapsule(&unmarked) {
+		return unmarked, nil
+	}
+
+	expr, diags := hclsyntax.ParseExpression(hclwrite.Format(tokens.Generate(&unmarked).Bytes()), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	literal, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("must be a literal value: %w", diags)
+	}
+
+	return literal, nil
+}
+
+func findAttribute(sig *BlockSignature, name string) *cty.Value {
+	for _, elem := range sig.GetElements() {
+		if elem.IsBodyAttribute() && elem.GetName() == name {
+			return elem.GetBodyAttribute()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package tfsig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/yoanm/go-tfsig"
+	"github.com/yoanm/go-tfsig/testutils"
+	"github.com/yoanm/go-tfsig/tokens"
+)
+
+func newVariable(name, typeExpr string, defaultValue *cty.Value) *tfsig.BlockSignature {
+	variable := tfsig.NewSignature("variable", name)
+	if typeExpr != "" {
+		variable.AppendAttribute("type", *tokens.NewExpressionValue(typeExpr))
+	}
+
+	tfsig.AppendAttributeIfNotNil(variable, "default", defaultValue)
+
+	return variable
+}
+
+func assertValidationErrors(t *testing.T, err error, expectedErrors []error, expectedMsg string) {
+	t.Helper()
+
+	if expectedErrors == nil {
+		if err != nil {
+			t.Errorf("Case \"%s\": unexpected error %v", t.Name(), err)
+		}
+
+		return
+	}
+
+	for _, expectedErr := range expectedErrors {
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("Case \"%s\": expected %v, got %v", t.Name(), expectedErr, err)
+		}
+	}
+
+	if err != nil && !strings.Contains(err.Error(), expectedMsg) {
+		t.Errorf("Case \"%s\": expected message to contain %q, got %q", t.Name(), expectedMsg, err)
+	}
+}
+
+func TestVarsFile(t *testing.T) {
+	t.Parallel()
+
+	vars := tfsig.NewVarsFile(tfsig.NewValueGenerator())
+	vars.Set("name", cty.StringVal("old"))
+
+	if err := vars.SetGo("tags", map[string]string{"Env": "prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vars.SetGo("subnets", []string{"10.0.1.0/24"}); err != nil {
+		t.Fatal(err)
+	}
+
+	vars.Set("password", cty.StringVal("secret").Mark(tfsig.Sensitive))
+	vars.Set("name", cty.StringVal("app"))
+
+	if err := vars.SetGo("invalid", struct{}{}); !errors.Is(err, tfsig.ErrUnsupportedType) {
+		t.Errorf("expected %v, got %v", tfsig.ErrUnsupportedType, err)
+	}
+
+	if actual := strings.Join(vars.GetNames(), ","); actual != "name,tags,subnets,password" {
+		t.Errorf("unexpected names %s", actual)
+	}
+
+	if value, isSet := vars.Get("name"); !isSet || !value.RawEquals(cty.StringVal("app")) {
+		t.Errorf("unexpected value %#v", value)
+	}
+
+	if _, isSet := vars.Get("unknown"); isSet {
+		t.Error("expected unknown variable to not be set")
+	}
+
+	expected := `name = "app"
+tags = {
+  Env = "prod"
+}
+subnets  = ["10.0.1.0/24"]
+password = "secret"
+`
+	if err := testutils.EnsureFileContentEquals(vars.Build(), expected); err != nil {
+		t.Error(err)
+	}
+
+	expected = strings.Replace(expected, `"secret"`, `"(sensitive)"`, 1)
+	if err := testutils.EnsureFileContentEquals(vars.BuildRedacted(), expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVarsFile_Validate(t *testing.T) {
+	t.Parallel()
+
+	defaultValue := cty.NumberIntVal(1)
+	variables := []*tfsig.BlockSignature{
+		newVariable("name", "string", nil),
+		newVariable("tags", "map(string)", nil),
+		newVariable("settings", "object({ size = number, tier = optional(string, \"free\") })", nil),
+		newVariable("anything", "", nil),
+		newVariable("count", "number", &defaultValue),
+		tfsig.NewResource("res_name", "res_id"),
+	}
+
+	cases := map[string]struct {
+		values         map[string]cty.Value
+		expectedErrors []error
+		expectedMsg    string
+	}{
+		"Valid": {
+			map[string]cty.Value{
+				"name":     cty.StringVal("app"),
+				"tags":     cty.ObjectVal(map[string]cty.Value{"Env": cty.StringVal("prod"), "Count": cty.True}),
+				"settings": cty.ObjectVal(map[string]cty.Value{"size": cty.StringVal("10")}),
+				"anything": *tokens.NewExpressionValue(`["a", 1]`),
+			},
+			nil,
+			"",
+		},
+		"Unknown and missing": {
+			map[string]cty.Value{
+				"name":     cty.StringVal("app"),
+				"settings": cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(1)}),
+				"anything": cty.True,
+				"other":    cty.True,
+			},
+			[]error{tfsig.ErrUnknownVariable, tfsig.ErrMissingVariable},
+			"unknown variable \"other\"\nmissing required variable \"tags\"",
+		},
+		"Invalid type": {
+			map[string]cty.Value{
+				"name":     cty.ListValEmpty(cty.String),
+				"tags":     cty.MapValEmpty(cty.String),
+				"settings": cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(1)}),
+				"anything": cty.True,
+			},
+			[]error{tfsig.ErrInvalidVariableValue},
+			"invalid variable value for \"name\": expected string: string required",
+		},
+		"Missing object attribute": {
+			map[string]cty.Value{
+				"name":     cty.StringVal("app"),
+				"tags":     cty.MapValEmpty(cty.String),
+				"settings": cty.EmptyObjectVal,
+				"anything": cty.True,
+			},
+			[]error{tfsig.ErrInvalidVariableValue},
+			"invalid variable value for \"settings\"",
+		},
+		"Not a literal": {
+			map[string]cty.Value{
+				"name":     *tokens.NewExpressionValue("var.name"),
+				"tags":     cty.MapValEmpty(cty.String),
+				"settings": cty.ObjectVal(map[string]cty.Value{"size": cty.NumberIntVal(1)}),
+				"anything": cty.True,
+			},
+			[]error{tfsig.ErrInvalidVariableValue},
+			"invalid variable value for \"name\": must be a literal value",
+		},
+	}
+
+	for tcname, tcase := range cases {
+		t.Run(
+			tcname,
+			func(t *testing.T) {
+				t.Parallel()
+
+				vars := tfsig.NewVarsFile(tfsig.NewValueGenerator())
+
+				for _, name := range []string{"name", "tags", "settings", "anything", "other"} {
+					if value, exists := tcase.values[name]; exists {
+						vars.Set(name, value)
+					}
+				}
+
+				assertValidationErrors(t, vars.Validate(variables...), tcase.expectedErrors, tcase.expectedMsg)
+			},
+		)
+	}
+}
+
+func TestVarsFile_Validate_invalidVariable(t *testing.T) {
+	t.Parallel()
+
+	vars := tfsig.NewVarsFile(tfsig.NewValueGenerator())
+	vars.Set("name", cty.StringVal("app"))
+
+	err := vars.Validate(newVariable("name", "list(foo)", nil))
+	if !errors.Is(err, tfsig.ErrInvalidVariable) {
+		t.Errorf("expected %v, got %v", tfsig.ErrInvalidVariable, err)
+	}
+}
+
+func TestVarsFile_WriteFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "prod.tfvars")
+	variable := newVariable("name", "string", nil)
+
+	vars := tfsig.NewVarsFile(tfsig.NewValueGenerator())
+	vars.Set("name", cty.NumberIntVal(1).Mark(tfsig.Sensitive))
+	vars.Set("other", cty.True)
+
+	if _, err := vars.WriteFile(path, variable); !errors.Is(err, tfsig.ErrUnknownVariable) {
+		t.Fatalf("expected %v, got %v", tfsig.ErrUnknownVariable, err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected file to not be written, got %v", err)
+	}
+
+	// Validation is skipped without variables
+	written, err := vars.WriteFile(path)
+	if err != nil || !written {
+		t.Fatalf("expected file to be written, got %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "name  = 1\nother = true\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
@@ -0,0 +1,45 @@
+package tfsig
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFS is a `fs.FS` able to write and remove files.
+type WriteFS interface {
+	fs.FS
+	// WriteFile writes data to the named file, creating it if necessary.
+	// Returned error is expected to mention the filename.
+	WriteFile(name string, data []byte) error
+	// Remove removes the named file.
+	// Returned error is expected to mention the filename.
+	Remove(name string) error
+}
+
+// NewDirFS returns a WriteFS for the tree of files rooted at the provided directory
+//
+// Files are written atomically (see `FileSignature.WriteFile()`).
+func NewDirFS(dir string) WriteFS { //nolint:ireturn // Implementation is private on purpose
+	return dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+/** Private **/
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d dirFS) WriteFile(name string, data []byte) error {
+	return writeFileAtomic(filepath.Join(d.dir, filepath.FromSlash(name)), data)
+}
+
+func (d dirFS) Remove(name string) error {
+	if err := os.Remove(filepath.Join(d.dir, filepath.FromSlash(name))); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", name, err)
+	}
+
+	return nil
+}